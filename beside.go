@@ -0,0 +1,27 @@
+package main
+
+import (
+    "errors"
+    "io"
+
+    "box/textbox"
+)
+
+// renderBeside writes the box of lines with the box of the lines read from
+// path to its right, gap columns apart. The shorter box is drawn as tall as
+// the other one. normalize is passed on to readFile.
+func renderBeside(w io.Writer, lines []string, path string, gap int, normalize bool, opts textbox.Options) error {
+    if opts.Width <= 0 {
+        return errors.New("--beside needs --width")
+    }
+    besideLines, err := readFile(path, normalize)
+    if err != nil {
+        return err
+    }
+
+    besideOpts := opts
+    besideOpts.Title, besideOpts.Footer, besideOpts.Width = "", "", 0
+    return textbox.Join(w, textbox.Horizontal, gap,
+        textbox.NewBox(lines, textbox.WithOptions(opts)),
+        textbox.NewBox(besideLines, textbox.WithOptions(besideOpts)))
+}
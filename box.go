@@ -2,169 +2,475 @@ package main
 
 import (
     "bufio"
+    "bytes"
+    "context"
+    "errors"
     "flag"
     "fmt"
+    "io"
+    "math"
     "os"
-    "strings"
-    "unicode/utf8"
+    "path/filepath"
+    "strconv"
+    "time"
 
+    "box/textbox"
     "github.com/mattn/go-runewidth"
+    "golang.org/x/term"
 )
 
-// BoxStyle contains the characters for the various frame components.
-type BoxStyle struct {
-    topLeft     string
-    topRight    string
-    bottomLeft  string
-    bottomRight string
-    horizontal  string
-    vertical    string
-    titleLeft   string
-    titleRight  string
-}
+// command describes a subcommand. setup declares the options of the command
+// on o and returns the function running it with the remaining arguments.
+type command struct {
+    setup func(o *optionSet) func(args []string) error
 
-// Different styles to choose from.
-var styles = map[int]BoxStyle{
-    1: {
-        topLeft: "┌", topRight: "┐", bottomLeft: "└", bottomRight: "┘",
-        horizontal: "─", vertical: "│", titleLeft: "┘", titleRight: "└",
-    },
-    2: {
-        topLeft: "╭", topRight: "╮", bottomLeft: "╰", bottomRight: "╯",
-        horizontal: "─", vertical: "│", titleLeft: "╯", titleRight: "╰",
-    },
-    3: {
-        topLeft: "╔", topRight: "╗", bottomLeft: "╚", bottomRight: "╝",
-        horizontal: "═", vertical: "║", titleLeft: "╝", titleRight: "╚",
-    },
+    // args are the words completed after the command name.
+    args []string
 }
 
-// visualLength returns the visual width of the string considering the character widths in different writing systems.
-func visualLength(s string) int {
-    return runewidth.StringWidth(s)
-}
+// commands maps subcommand names to their implementation. Without a known
+// subcommand the arguments are handed to the box command.
+var commands map[string]command
 
-// max returns the larger of two integers.
-func max(a, b int) int {
-    if a > b {
-        return a
+func init() {
+    commands = map[string]command{
+        "box":        {setup: boxCommand},
+        "table":      {setup: tableCommand},
+        "styles":     {setup: stylesCommand, args: []string{"list", "show", "add"}},
+        "themes":     {setup: themesCommand, args: []string{"list", "show"}},
+        "completion": {setup: completionCommand, args: completionShells},
+        "config":     {setup: configCommand, args: []string{"init", "show"}},
     }
-    return b
 }
 
-func repeatChar(char string, count int) string {
-    result := ""
-    for i := 0; i < count; i++ {
-        result += char
+// commandOptions creates the option set of the named command.
+func commandOptions(prog, name string) (*optionSet, func(args []string) error) {
+    fullName := prog
+    if name != "box" {
+        fullName += " " + name
     }
-    return result
+    o := newOptionSet(fullName)
+    o.prog = prog
+    return o, commands[name].setup(o)
 }
 
 func main() {
-    // Read parameters.
-    styleNum := flag.Int("n", 1, "Box style (1-4)")
-    customChar := flag.String("f", "", "Custom UTF-8 character for style 4")
-    title := flag.String("t", "", "Box title")
-    center := flag.Bool("c", false, "Center text")
-    flag.Parse()
-
-    var style BoxStyle
-
-    // Validate style number and custom character.
-    if *styleNum >= 1 && *styleNum <= 3 {
-        style = styles[*styleNum]
-    } else if *styleNum == 4 {
-        // Trim whitespace and validate rune count.
-        utfChar := strings.TrimSpace(*customChar)
-        if utf8.RuneCountInString(utfChar) != 1 {
-            fmt.Fprintln(os.Stderr, "Error: For -n 4, exactly one UTF-8 character must be provided with -f.")
-            os.Exit(1)
-        }
-        style = BoxStyle{
-            topLeft: utfChar, topRight: utfChar, bottomLeft: utfChar, bottomRight: utfChar,
-            horizontal: utfChar, vertical: utfChar, titleLeft: utfChar, titleRight: utfChar,
-        }
-    } else {
-        fmt.Fprintln(os.Stderr, "Invalid style number or missing custom character. Please use -n 1-4 or provide a custom character with -f.")
-        os.Exit(1)
-    }
+    ignoreBrokenPipe()
+    os.Exit(run(os.Args, os.Stderr))
+}
 
-    // Read input lines and calculate maximum content width.
-    var lines []string
-    scanner := bufio.NewScanner(os.Stdin)
-    maxContentWidth := 0
-    for scanner.Scan() {
-        line := scanner.Text()
-        lines = append(lines, line)
-        if l := visualLength(line); l > maxContentWidth {
-            maxContentWidth = l
+// run runs the command named by args, the program name followed by the
+// command line, writes its error to stderr and returns the exit status.
+func run(args []string, stderr io.Writer) int {
+    prog := filepath.Base(args[0])
+    args = args[1:]
+    name := "box"
+    if len(args) > 0 {
+        if _, ok := commands[args[0]]; ok {
+            name, args = args[0], args[1:]
+        }
+    }
+    err := registerUserStyles()
+    if err == nil {
+        o, command := commandOptions(prog, name)
+        o.fs.SetOutput(stderr)
+        if err = o.Parse(args); errors.Is(err, flag.ErrHelp) {
+            return exitOK
+        } else if err == nil {
+            err = command(o.fs.Args())
+        }
+    }
+    var reported *reportedError
+    if code := exitCode(err); code != exitOK {
+        // The reader of a broken pipe has all it wanted, as with head, and
+        // an interrupted box has been closed on purpose.
+        if code != exitBrokenPipe && code != exitInterrupted && !errors.As(err, &reported) {
+            fmt.Fprintln(stderr, err)
         }
+        return code
     }
+    return exitOK
+}
+
+// boxCommand declares the options of the box command which frames its input.
+func boxCommand(o *optionSet) func(args []string) error {
+    var (
+        config      string
+        showVersion bool
+        verbose     bool
+        debug       bool
+        listStyles  bool
+        beside      string
+        besideGap   int
+        noFallback  bool
+        wide        bool
+        locale      string
+        ruler       bool
+        metadata    bool
+        widthCheck  widthCheck
+        partial     bool
+        metaPrefix  string
+        mermaid     string
+        plantUML    bool
+        empty       string
+        noNormalize bool
+        rules       colorRules
+        follow      bool
+        osc52       bool
+        diff        bool
+        compare     string
+        ignoreSpace bool
+        transient   float64
+    )
+    opts := textbox.DefaultOptions()
+    registerStyleOptions(o, &opts)
+    o.Bool(&listStyles, "", "list-styles", false, "Style", "List the available style names and exit")
+    o.Bool(&opts.FillBlock, "", "fill-block", false, "Style", "Draw the horizontal borders as a solid block bar")
+    o.Bool(&opts.AutoStyle, "", "auto-style", false, "Style", "With --width, switch to a style whose border divides the width evenly")
+    o.String(&opts.BorderColor, "", "border-color", "", "Style", "Border color: a name, SGR number, 256 color index, #rrggbb or rgb(r,g,b)")
+    o.String(&opts.Theme, "", "theme", "", "Style", "Theme name or JSON theme `FILE`, replacing --style and coloring the box")
+    o.complete("theme", "themes list")
+    o.Bool(&opts.NoColor, "", "no-color", false, "Style", "Draw without colors and text attributes")
+    o.Bool(&opts.ASCII, "", "ascii", false, "Style", "Draw the border with ASCII characters")
+    o.Bool(&noFallback, "", "no-ascii-fallback", false, "Style", "Keep box drawing characters on consoles that seem unable to show them")
+    o.Var(&opts.Shadow, "", "shadow", "Style", "Shadow: none (the theme's), drop below and right of the box, or inner")
+    o.complete("shadow", "", "none", "drop", "inner")
+    o.Bool(&opts.InnerBorder, "", "inner-border", false, "Style", "Draw a second, single line border one space inside the frame")
+    o.String(&opts.Title, "t", "title", "", "Title", "Box title")
+    o.String(&opts.TitleStyle, "", "title-style", "", "Title", "Draw the top border with the title in another `STYLE`, given like --style, joined to the sides")
+    o.complete("title-style", "styles --names list", "1", "2", "3", "4")
+    o.Var(&opts.TitleAlign, "", "title-align", "Title", "Place the title of boxes with content at the left, center or right")
+    o.complete("title-align", "", "left", "center", "right")
+    o.Var(&opts.TitleOnlyAlign, "", "align-title", "Title", "Place the title of boxes without content at the left, center or right")
+    o.complete("align-title", "", "left", "center", "right")
+    o.String(&opts.Footer, "", "footer", "", "Title", "Text embedded in the bottom border")
+    o.Var(&opts.FooterAlign, "", "footer-align", "Title", "Place the footer at the left, center or right")
+    o.complete("footer-align", "", "left", "center", "right")
+    o.String(&opts.TitleColor, "", "title-color", "", "Title", "Title color, like --border-color")
+    o.Bool(&opts.TitleUnderline, "", "title-underline", false, "Title", "Underline the title text (terminals only, not with NO_COLOR)")
+    o.Var(&opts.TitleFit, "", "title-fit", "Title", "Widen the box for a long title (grow) or cut the title with an ellipsis (clip)")
+    o.complete("title-fit", "", "grow", "clip")
+    o.Bool(&opts.TitleWrap, "", "title-wrap", false, "Title", "With --width, wrap a title too wide for the box onto further top border rows")
+    o.Bool(&opts.TitleTab, "", "title-tab", false, "Title", "Draw the title in a tab above the top border, like a file folder")
+    o.Int(&opts.TitleMinBody, "", "title-min-body", 0, "Title", "Minimum interior width, so title-only boxes are not too narrow")
+    o.Int(&opts.Width, "w", "width", 0, "Layout", "Total width of the box; longer lines are cut")
+    o.Int(&opts.Height, "", "height", 0, "Layout", "Total height of the box; further lines are cut")
+    o.Int(&opts.Wrap, "", "wrap", 0, "Layout", "Wrap lines wider than N columns")
+    o.String((*string)(&opts.WrapMode), "", "wrap-mode", string(opts.WrapMode), "Layout", "Wrap at word boundaries (soft) or at exactly N columns (hard)")
+    o.complete("wrap-mode", "", "soft", "hard")
+    o.Bool(&opts.Hyphenate, "", "hyphenate", false, "Layout", "End the pieces of words too long for soft wrapping with a hyphen")
+    o.Int(&opts.WrapIndent, "", "wrap-indent", 0, "Layout", "Indent continuation lines of wrapped lines by N spaces")
+    o.Bool(&opts.PreserveIndent, "", "preserve-indent", false, "Layout", "With --wrap, indent continuation lines like the line they continue")
+    o.String(&opts.ContinueChar, "", "continue-char", opts.ContinueChar, "Layout", "Character ending lines broken by hard wrapping")
+    o.Var(&opts.Overflow, "", "overflow", "Layout", "Content beyond --width or --height: clip it, wrap it or fail with an error")
+    o.complete("overflow", "", "clip", "wrap", "error")
+    o.Bool(&opts.Reflow, "", "reflow", false, "Layout", "With --width and --height, wrap so the content evenly fills the box")
+    o.Bool(&follow, "", "follow", false, "Layout", "With --width, draw the lines as they arrive; Ctrl-C closes the box")
+    o.String(&beside, "", "beside", "", "Layout", "With --width, draw a second box with the lines of `FILE` to the right")
+    o.Int(&besideGap, "", "beside-gap", 2, "Layout", "Columns between the box and the --beside box")
+    o.String(&compare, "", "compare", "", "Content", "Mark the lines not found in `FILE` and count them in the footer")
+    o.Bool(&ignoreSpace, "", "compare-ignore-space", false, "Content", "With --compare, match lines that differ only in whitespace")
+    o.Bool(&diff, "", "diff", false, "Layout", "Draw the two files given as arguments in boxes side by side, lining up their common lines")
+    o.Bool(&opts.TitleSep, "", "title-sep", false, "Content", "Draw a divider below the header lines")
+    o.Int(&opts.HeaderLines, "", "header-lines", opts.HeaderLines, "Content", "Number of header lines above the --title-sep divider")
+    o.String(&opts.SectionMarker, "", "section-marker", "", "Content", "Draw lines starting with `PREFIX`, such as \"## \", as dividers labeled with the rest of the line")
+    o.String(&opts.ControlChars, "", "control-chars", opts.ControlChars, "Content", "Show control characters as strip, caret (^G), pictures (␇) or keep them")
+    o.complete("control-chars", "", "strip", "caret", "pictures", "keep")
+    o.String(&opts.Sanitize, "", "sanitize", "", "Content", "Remove escape sequences: strict (all), sgr (all but colors) or off (default sgr for piped input)")
+    o.complete("sanitize", "", "strict", "sgr", "off")
+    o.String(&opts.Normalize, "", "normalize", "none", "Content", "Normalize the text to Unicode nfc or nfkc (which also folds full-width letters) before measuring, or keep it (none)")
+    o.complete("normalize", "", "nfc", "nfkc", "none")
+    o.String(&empty, "", "empty", "minimal", "Content", "For empty input fail with an error, draw a minimal box or a box with one blank row")
+    o.complete("empty", "", "error", "minimal", "blank")
+    o.Bool(&partial, "", "partial-on-error", false, "Content", "After a read error, draw the lines read so far with a last line marking the box incomplete")
+    o.Bool(&noNormalize, "", "no-normalize-newlines", false, "Content", "Keep bare carriage returns inside lines instead of breaking lines at them")
+    o.Bool(&opts.Reverse, "", "reverse", false, "Content", "Reverse the order of the lines")
+    o.Int(&opts.MaxLines, "", "max-lines", 0, "Content", "Show at most N lines (after --reverse) and count the rest")
+    o.Bool(&opts.Collapse, "", "collapse", false, "Content", "With more than --max-lines lines, show only the title and the line count")
+    o.Bool(&wide, "", "ambiguous-wide", runewidth.EastAsianWidth, "Content", "Measure East Asian ambiguous characters two columns wide (default from the locale)")
+    o.String(&locale, "", "locale", "", "Content", "Measure ambiguous characters like the wcwidth of `LOCALE`, such as ja_JP.UTF-8, instead of LC_CTYPE")
+    o.Var(&rules, "", "color-rule", "Content", "Color the lines matching regexps, first match wins: \"ERROR=red,WARN=yellow\" (repeatable)")
+    o.Var(&opts.TextAlign, "", "align", "Content", "Align the lines left, center, right or justify them")
+    o.complete("align", "", "left", "center", "right", "justify")
+    o.alias("align", "align-content")
+    o.Bool(&opts.Center, "c", "center", false, "Content", "Center text")
+    o.Bool(&opts.Tree, "", "tree", false, "Content", "Draw indented lines as boxes nested in the box of the lines above them")
+    o.Int(&opts.TreeIndent, "", "tree-indent", opts.TreeIndent, "Content", "Columns of indentation per --tree level")
+    o.Bool(&opts.Vertical, "", "vertical", false, "Content", "Write one character per line in a tall, narrow box")
+    o.Bool(&opts.LineNumbers, "ln", "line-numbers", false, "Content", "Number the lines")
+    o.Bool(&opts.Superscript, "", "superscript", false, "Content", "Write line numbers in superscript digits")
+    o.Bool(&opts.Subscript, "", "subscript", false, "Content", "Write line numbers in subscript digits")
+    o.Var((*progressValue)(&opts.Progress), "", "progress", "Content", "Add a progress bar filled to the fraction `N` (0 to 1) below the lines; negative for none")
+    o.String(&opts.ProgressFill, "", "progress-fill", opts.ProgressFill, "Content", "Glyph of the filled part of the --progress bar")
+    o.String(&opts.ProgressEmpty, "", "progress-empty", opts.ProgressEmpty, "Content", "Glyph of the empty part of the --progress bar")
+    o.Bool(&opts.ProgressLabel, "", "progress-label", false, "Content", "Write the percentage after the --progress bar")
+    o.Bool(&opts.Overlay, "", "overlay", false, "Content", "Skip over padding with cursor movements instead of spaces, so the screen shows through (TTY only)")
+    o.String(&mermaid, "", "mermaid", "", "Output", "Write the content as a Mermaid note over `PARTICIPANT` instead of drawing a box")
+    o.Bool(&plantUML, "", "plantuml", false, "Output", "Write the content as a PlantUML note instead of drawing a box")
+    o.Float(&transient, "", "transient", 0, "Output", "On a terminal, erase the box again after `SECONDS`, for passing notices")
+    o.Bool(&osc52, "", "osc52", false, "Output", "Also set the terminal clipboard to the box with an OSC 52 escape, which works over SSH")
+    o.Var(&widthCheck, "", "check-width", "Output", "Warn on stderr if the box is wider than the terminal; with =strict fail without drawing it")
+    o.complete("check-width", "", "warn", "strict", "off")
+    o.Bool(&metadata, "", "metadata", false, "Output", "Precede the box with comment lines naming the kind of every row and the border columns")
+    o.String(&metaPrefix, "", "metadata-prefix", "# ", "Output", "Start the --metadata comment lines with `PREFIX`")
+    o.String(&config, "", "config", "", "Configuration", "Read options from a JSON or TOML file on top of the user and directory configuration")
+    o.Bool(&showVersion, "", "version", false, "Information", "Print version information and exit")
+    o.Bool(&verbose, "", "verbose", false, "Information", "Print detailed information")
+    o.Bool(&debug, "", "debug", false, "Information", "Write width and option diagnostics to stderr")
+    o.Bool(&ruler, "", "ruler", false, "Information", "Print a column ruler above the box to check its alignment")
+    o.commandLineOnly("list-styles", "config", "version", "verbose", "debug", "ruler")
 
-    minPadding := 2
-    innerWidth := maxContentWidth + minPadding
+    return func(args []string) error {
+        if showVersion {
+            printVersion(os.Stdout, o.prog, verbose)
+            return nil
+        }
+        if err := applyConfig(o, config, &opts); err != nil {
+            return err
+        }
+        if listStyles {
+            for _, name := range textbox.Styles() {
+                if _, err := fmt.Fprintln(os.Stdout, name); err != nil {
+                    return err
+                }
+            }
+            return nil
+        }
+        if locale != "" && o.sources["ambiguous-wide"] == "" {
+            wide = localeAmbiguousWide(locale)
+        }
+        textbox.SetAmbiguousWide(wide)
+        opts.HighlightRules = append(opts.HighlightRules, rules.rules...)
+        if debug {
+            opts.Debug = os.Stderr
+            debugOptions(o)
+            debugTerminal()
+            mode := "narrow (1 column)"
+            if wide {
+                mode = "wide (2 columns)"
+            }
+            debugf("ambiguous characters: %s", mode)
+        }
+        if empty != "error" && empty != "minimal" && empty != "blank" {
+            return fmt.Errorf("invalid --empty policy %q, use error, minimal or blank", empty)
+        }
+        if mermaid != "" && plantUML {
+            return errors.New("--mermaid and --plantuml cannot be combined")
+        }
+        if diff && len(args) != 2 {
+            return fmt.Errorf("--diff needs two files to compare, got %d", len(args))
+        }
+        if diff && follow {
+            return errors.New("--diff and --follow cannot be combined")
+        }
+        if transient < 0 || math.IsNaN(transient) {
+            return fmt.Errorf("invalid --transient delay %v, want seconds", transient)
+        }
+        if compare != "" && (diff || follow) {
+            return errors.New("--compare cannot be combined with --diff or --follow")
+        }
+        if err := loadTheme(opts.Theme); err != nil {
+            return err
+        }
+        if err := checkStyle(opts); err != nil {
+            return err
+        }
+        if !setupConsole() && !noFallback && !opts.ASCII {
+            fmt.Fprintln(os.Stderr, "note: the console cannot show box drawing characters, using ASCII (--no-ascii-fallback keeps them)")
+            opts.ASCII = true
+        }
+        if !colorEnabled() {
+            opts.NoColor = true
+        }
 
-    // Handle title decoration.
-    var titleDecor string
-    if *title != "" {
-        titleDecor = style.titleLeft + " " + *title + " " + style.titleRight
-        if visualLength(titleDecor) > innerWidth {
-            innerWidth = visualLength(titleDecor)
+        if opts.Sanitize == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+            opts.Sanitize = "sgr"
+        }
+        // With --osc52 the output is also collected for the clipboard,
+        // which is set once the box is complete.
+        var out io.Writer = os.Stdout
+        var clip bytes.Buffer
+        if osc52 {
+            out = io.MultiWriter(os.Stdout, &clip)
         }
+        copyBox := func() error {
+            if !osc52 {
+                return nil
+            }
+            return writeOSC52(os.Stdout, clip.String())
+        }
+        // A transient box is erased again; elsewhere than on a terminal it
+        // stays like any other.
+        var shown bytes.Buffer
+        erase := transient > 0 && term.IsTerminal(int(os.Stdout.Fd()))
+        if erase {
+            out = io.MultiWriter(out, &shown)
+        }
+        if diff {
+            if err := renderDiff(out, args[0], args[1], !noNormalize, opts); err != nil {
+                return err
+            }
+            return copyBox()
+        }
+        if follow {
+            ctx, stop := interruptContext()
+            defer stop()
+            err := followInput(ctx, os.Stdin, out, !noNormalize, opts)
+            if err == nil || errors.Is(err, context.Canceled) {
+                if err := copyBox(); err != nil {
+                    return err
+                }
+            }
+            if errors.Is(err, context.Canceled) {
+                return context.Cause(ctx)
+            }
+            return err
+        }
+        // drawLines writes the lines in the selected output format.
+        drawLines := func(lines []string) error {
+            switch {
+            case mermaid != "":
+                return writeMermaidNote(out, mermaid, lines, opts)
+            case plantUML:
+                return writePlantUMLNote(out, lines, opts)
+            }
+            if err := checkWidth(os.Stderr, widthCheck, lines, opts); err != nil {
+                return err
+            }
+            if metadata {
+                if err := writeMetadata(out, metaPrefix, lines, opts); err != nil {
+                    return err
+                }
+            }
+            if ruler {
+                if err := writeRuler(out, lines, opts); err != nil {
+                    return err
+                }
+            }
+            if beside != "" {
+                return renderBeside(out, lines, beside, besideGap, !noNormalize, opts)
+            }
+            return textbox.NewBox(lines, textbox.WithOptions(opts)).Render(out)
+        }
+        lines, readErr := readLines(os.Stdin, "stdin", !noNormalize)
+        if readErr != nil {
+            if !partial {
+                return readErr
+            }
+            lines = append(lines, fmt.Sprintf("[incomplete: read error after %d lines]", len(lines)))
+        }
+        if len(lines) == 0 {
+            switch empty {
+            case "error":
+                return &readError{name: "stdin", err: errors.New("empty input")}
+            case "blank":
+                lines = []string{""}
+            }
+        }
+        if compare != "" {
+            marked, differ, err := compareLines(lines, compare, ignoreSpace, !noNormalize)
+            if err != nil {
+                return err
+            }
+            lines, opts.Footer = marked, compareFooter(opts.Footer, differ, len(marked))
+            if opts.HighlightRules, err = appendDiffColors(opts.HighlightRules); err != nil {
+                return err
+            }
+        }
+        if err := drawLines(lines); err != nil {
+            return err
+        }
+        if err := copyBox(); err != nil {
+            return err
+        }
+        if erase {
+            if err := eraseAfter(os.Stdout, shown.String(), time.Duration(transient*float64(time.Second))); err != nil {
+                return err
+            }
+        }
+        // The partial box is drawn, but the input was not read in full.
+        return readErr
     }
+}
+
+// progressValue is the value of --progress. Without a bar it is negative
+// and shown as empty, not as the value standing for none.
+type progressValue float64
 
-    // Generate the top border.
-    if *title != "" {
-        remaining := innerWidth - visualLength(titleDecor)
-        leftFill := remaining / 2
-        rightFill := remaining - leftFill
-        leftHor := repeatChar(style.horizontal, leftFill/visualLength(style.horizontal))
-        rightHor := repeatChar(style.horizontal, rightFill/visualLength(style.horizontal))
-        fmt.Printf("%s%s%s%s%s\n",
-            style.topLeft,
-            leftHor,
-            titleDecor,
-            rightHor,
-            style.topRight)
-    } else {
-        lineWidth := innerWidth / visualLength(style.horizontal)
-        fmt.Printf("%s%s%s\n",
-            style.topLeft,
-            repeatChar(style.horizontal, lineWidth),
-            style.topRight)
+func (p *progressValue) String() string {
+    if *p < 0 {
+        return ""
     }
+    return strconv.FormatFloat(float64(*p), 'g', -1, 64)
+}
 
-    // Print the content.
-    for _, line := range lines {
-        pad := innerWidth - visualLength(line)
-        if *center {
-            leftPad := pad / 2
-            rightPad := pad - leftPad
-            fmt.Printf("%s%s%s%s%s\n",
-                style.vertical,
-                strings.Repeat(" ", leftPad),
-                line,
-                strings.Repeat(" ", rightPad),
-                style.vertical)
-        } else {
-            leftPad := 1
-            rightPad := pad - leftPad
-            if rightPad < 0 {
-                rightPad = 0
-            }
-            fmt.Printf("%s%s%s%s%s\n",
-                style.vertical,
-                strings.Repeat(" ", leftPad),
-                line,
-                strings.Repeat(" ", rightPad),
-                style.vertical)
-        }
+func (p *progressValue) Set(value string) error {
+    f, err := strconv.ParseFloat(value, 64)
+    if err != nil {
+        return fmt.Errorf("invalid fraction %q", value)
     }
+    *p = progressValue(f)
+    return nil
+}
 
-    // Generate the bottom border.
-    lineWidth := innerWidth / visualLength(style.horizontal)
-    fmt.Printf("%s%s%s\n",
-        style.bottomLeft,
-        repeatChar(style.horizontal, lineWidth),
-        style.bottomRight)
-}
\ No newline at end of file
+// Get returns the fraction, for config show.
+func (p *progressValue) Get() any { return float64(*p) }
+
+// readError is a failure to read the input named name after the first
+// lines lines.
+type readError struct {
+    name  string
+    lines int
+    err   error
+}
+
+func (e *readError) Error() string {
+    if e.lines == 0 {
+        return fmt.Sprintf("reading %s: %v", e.name, e.err)
+    }
+    return fmt.Sprintf("reading %s: %v (after %d lines)", e.name, e.err, e.lines)
+}
+
+func (e *readError) Unwrap() error { return e.err }
+
+// readLines reads all input lines from r, named name in errors. Lines end
+// at \n or \r\n and, with normalize, also at a bare \r. After a read error
+// the lines read before it are returned with a *readError.
+func readLines(r io.Reader, name string, normalize bool) ([]string, error) {
+    var lines []string
+    scanner := bufio.NewScanner(r)
+    if normalize {
+        scanner.Split(scanNewlines)
+    }
+    for scanner.Scan() {
+        lines = append(lines, scanner.Text())
+    }
+    if err := scanner.Err(); err != nil {
+        return lines, &readError{name: name, lines: len(lines), err: err}
+    }
+    return lines, nil
+}
+
+// scanNewlines is a bufio.SplitFunc like bufio.ScanLines that also ends
+// lines at a carriage return not followed by a newline.
+func scanNewlines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+    if atEOF && len(data) == 0 {
+        return 0, nil, nil
+    }
+    if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+        switch {
+        case data[i] == '\n':
+            return i + 1, data[:i], nil
+        case i+1 < len(data) && data[i+1] == '\n':
+            return i + 2, data[:i], nil
+        case i+1 < len(data) || atEOF:
+            return i + 1, data[:i], nil
+        }
+        // Wait for the byte after the \r to tell \r\n from \r.
+        return 0, nil, nil
+    }
+    if atEOF {
+        return len(data), data, nil
+    }
+    return 0, nil, nil
+}
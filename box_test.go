@@ -0,0 +1,117 @@
+package main
+
+import (
+    "encoding/base64"
+    "errors"
+    "io"
+    "reflect"
+    "strings"
+    "testing"
+    "testing/iotest"
+
+    "box/textbox"
+)
+
+func TestReadLines(t *testing.T) {
+    for _, tc := range []struct {
+        in        string
+        normalize bool
+        want      []string
+    }{
+        {"a\nb\n", true, []string{"a", "b"}},
+        {"a\r\nb\r\n", true, []string{"a", "b"}},
+        {"a\rb\r", true, []string{"a", "b"}},
+        {"a\r\rb", true, []string{"a", "", "b"}},
+        {"a\n\r\nb", true, []string{"a", "", "b"}},
+        {"a\rb\r\nc\nd", true, []string{"a", "b", "c", "d"}},
+        {"a\r", true, []string{"a"}},
+        {"", true, nil},
+        {"a\rb\r\nc", false, []string{"a\rb", "c"}},
+        {"a\r", false, []string{"a"}},
+    } {
+        got, _ := readLines(strings.NewReader(tc.in), "test", tc.normalize)
+        if !reflect.DeepEqual(got, tc.want) {
+            t.Errorf("readLines(%q, %v) = %q, want %q", tc.in, tc.normalize, got, tc.want)
+        }
+        // A \r at the end of one read must wait for the next to tell \r\n
+        // from a bare \r.
+        got, _ = readLines(iotest.OneByteReader(strings.NewReader(tc.in)), "test", tc.normalize)
+        if !reflect.DeepEqual(got, tc.want) {
+            t.Errorf("readLines(%q, %v) one byte at a time = %q, want %q", tc.in, tc.normalize, got, tc.want)
+        }
+    }
+}
+
+func TestReadLinesError(t *testing.T) {
+    failing := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errors.New("device gone")))
+    got, err := readLines(failing, "stdin", true)
+    if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+        t.Errorf("lines before the error = %q, want %q", got, want)
+    }
+    want := "reading stdin: device gone (after 2 lines)"
+    if err == nil || err.Error() != want {
+        t.Errorf("error = %v, want %q", err, want)
+    }
+    if code := exitCode(err); code != exitInput {
+        t.Errorf("exit status %d, want %d", code, exitInput)
+    }
+}
+
+func TestLocaleAmbiguousWide(t *testing.T) {
+    for _, tc := range []struct {
+        locale string
+        want   bool
+    }{
+        {"ja_JP.UTF-8", true},
+        {"zh_TW.utf8", true},
+        {"ko_KR.EUC-KR", true},
+        {"ja_JP.UTF-8@cjk_narrow", false},
+        {"ja_JP", false},
+        {"en_US.UTF-8", false},
+        {"en_US.eucJP", true},
+        {"C.UTF-8", false},
+        {"POSIX", false},
+    } {
+        if got := localeAmbiguousWide(tc.locale); got != tc.want {
+            t.Errorf("localeAmbiguousWide(%q) = %v, want %v", tc.locale, got, tc.want)
+        }
+    }
+}
+
+func TestWriteMetadata(t *testing.T) {
+    opts := textbox.DefaultOptions()
+    opts.TitleSep = true
+    var buf strings.Builder
+    if err := writeMetadata(&buf, "// ", []string{"head", "body"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := `// box width=8 height=5
+// columns border=0 interior=1-6 border=7
+// row 1 border
+// row 2 content
+// row 3 divider
+// row 4 content
+// row 5 border
+`
+    if buf.String() != want {
+        t.Errorf("writeMetadata =\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
+
+func TestWriteOSC52(t *testing.T) {
+    var buf strings.Builder
+    if err := writeOSC52(&buf, "┌───┐\n│ \x1b[31mx\x1b[0m │\n└───┘\n"); err != nil {
+        t.Fatal(err)
+    }
+    payload, ok := strings.CutPrefix(buf.String(), "\x1b]52;c;")
+    if !ok || !strings.HasSuffix(payload, "\a") {
+        t.Fatalf("writeOSC52 wrote %q, want an OSC 52 sequence", buf.String())
+    }
+    text, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(payload, "\a"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := "┌───┐\n│ x │\n└───┘\n"; string(text) != want {
+        t.Errorf("clipboard text = %q, want %q", text, want)
+    }
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+
+    "box/textbox"
+    "golang.org/x/term"
+)
+
+// widthCheck is the value of --check-width: off, warn or strict. Given
+// without a value the option warns.
+type widthCheck string
+
+// Width checks.
+const (
+    checkOff    widthCheck = ""
+    checkWarn   widthCheck = "warn"
+    checkStrict widthCheck = "strict"
+)
+
+func (c *widthCheck) String() string { return string(*c) }
+
+func (c *widthCheck) Set(value string) error {
+    switch value {
+    case "true", "warn":
+        *c = checkWarn
+    case "false", "off", "":
+        *c = checkOff
+    case "strict":
+        *c = checkStrict
+    default:
+        return fmt.Errorf("invalid width check %q, use warn, strict or off", value)
+    }
+    return nil
+}
+
+// IsBoolFlag lets --check-width stand alone.
+func (c *widthCheck) IsBoolFlag() bool { return true }
+
+// termSize returns the size of the terminal stdout is connected to.
+func termSize() (width, height int, err error) {
+    return term.GetSize(int(os.Stdout.Fd()))
+}
+
+// terminalWidth returns the width of the terminal stdout is connected to,
+// or else the COLUMNS environment variable, and whether either was found.
+func terminalWidth() (int, bool) {
+    if width, _, err := termSize(); err == nil && width > 0 {
+        return width, true
+    }
+    if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+        return width, true
+    }
+    return 0, false
+}
+
+// checkWidth compares the width of the box framing lines with the terminal
+// width. A box too wide is reported on stderr, or with strict is an error.
+// Without a known terminal width nothing is checked.
+func checkWidth(stderr io.Writer, check widthCheck, lines []string, opts textbox.Options) error {
+    if check == checkOff {
+        return nil
+    }
+    termWidth, ok := terminalWidth()
+    if !ok {
+        return nil
+    }
+    d, err := textbox.Measure(lines, textbox.WithOptions(opts))
+    if err != nil {
+        return err
+    }
+    if d.Width <= termWidth {
+        return nil
+    }
+    if check == checkStrict {
+        return fmt.Errorf("the box is %d columns wide, the terminal %d", d.Width, termWidth)
+    }
+    fmt.Fprintf(stderr, "warning: the box is %d columns wide, the terminal %d\n", d.Width, termWidth)
+    return nil
+}
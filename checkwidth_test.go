@@ -0,0 +1,56 @@
+package main
+
+import (
+    "strings"
+    "testing"
+
+    "box/textbox"
+)
+
+func TestCheckWidth(t *testing.T) {
+    if _, _, err := termSize(); err == nil {
+        t.Skip("stdout is a terminal")
+    }
+    t.Setenv("COLUMNS", "10")
+    lines := []string{"twelve chars"}
+    opts := textbox.DefaultOptions()
+
+    var stderr strings.Builder
+    if err := checkWidth(&stderr, checkWarn, lines, opts); err != nil {
+        t.Fatal(err)
+    }
+    if want := "warning: the box is 16 columns wide, the terminal 10\n"; stderr.String() != want {
+        t.Errorf("warning = %q, want %q", stderr.String(), want)
+    }
+    stderr.Reset()
+    if err := checkWidth(&stderr, checkStrict, lines, opts); err == nil || stderr.Len() > 0 {
+        t.Errorf("strict check: error %v, stderr %q, want only an error", err, stderr.String())
+    }
+    if err := checkWidth(&stderr, checkStrict, []string{"fits"}, opts); err != nil || stderr.Len() > 0 {
+        t.Errorf("strict check of a narrow box: error %v, stderr %q", err, stderr.String())
+    }
+    t.Setenv("COLUMNS", "")
+    if err := checkWidth(&stderr, checkStrict, lines, opts); err != nil {
+        t.Errorf("strict check without a terminal width: %v", err)
+    }
+}
+
+func TestCheckWidthOption(t *testing.T) {
+    for _, tc := range []struct {
+        args []string
+        want widthCheck
+    }{
+        {nil, checkOff},
+        {[]string{"--check-width"}, checkWarn},
+        {[]string{"--check-width=strict"}, checkStrict},
+        {[]string{"--check-width=false"}, checkOff},
+    } {
+        o, _ := commandOptions("box", "box")
+        if err := o.Parse(tc.args); err != nil {
+            t.Fatal(err)
+        }
+        if got := o.fs.Lookup("check-width").Value.String(); got != string(tc.want) {
+            t.Errorf("%q: --check-width = %q, want %q", tc.args, got, tc.want)
+        }
+    }
+}
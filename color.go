@@ -0,0 +1,54 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+
+    "box/textbox"
+    "golang.org/x/term"
+)
+
+// colorEnabled reports whether colors and text attributes are written: stdout
+// is a terminal and NO_COLOR is not set.
+func colorEnabled() bool {
+    if os.Getenv("NO_COLOR") != "" {
+        return false
+    }
+    return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// colorRules collects the highlight rules of every --color-rule option in
+// order. Each value holds comma separated PATTERN=COLOR rules.
+type colorRules struct {
+    specs []string
+    rules []textbox.HighlightRule
+}
+
+func (c *colorRules) String() string {
+    return strings.Join(c.specs, ",")
+}
+
+func (c *colorRules) Set(value string) error {
+    for _, spec := range strings.Split(value, ",") {
+        i := strings.LastIndex(spec, "=")
+        if i < 0 {
+            return fmt.Errorf("color rule %q: expected PATTERN=COLOR", spec)
+        }
+        pattern, err := regexp.Compile(spec[:i])
+        if err != nil {
+            return fmt.Errorf("color rule %q: %v", spec, err)
+        }
+        color, err := textbox.ParseANSIColor(spec[i+1:])
+        if err != nil {
+            return fmt.Errorf("color rule %q: %v", spec, err)
+        }
+        c.specs = append(c.specs, spec)
+        c.rules = append(c.rules, textbox.HighlightRule{Pattern: pattern, Color: color})
+    }
+    return nil
+}
+
+// repeatable marks the option as collecting every value given.
+func (c *colorRules) repeatable() {}
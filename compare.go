@@ -0,0 +1,48 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+)
+
+// compareLines marks the lines that do not appear in the file path with the
+// gutter of a changed diff line, and the others with that of a common line.
+// With ignoreSpace lines differing only in whitespace match. It returns the
+// marked lines and the number of lines that differ.
+func compareLines(lines []string, path string, ignoreSpace, normalize bool) ([]string, int, error) {
+    golden, err := readFile(path, normalize)
+    if err != nil {
+        return nil, 0, err
+    }
+    key := func(line string) string {
+        if ignoreSpace {
+            return strings.Join(strings.Fields(line), " ")
+        }
+        return line
+    }
+    known := make(map[string]bool, len(golden))
+    for _, line := range golden {
+        known[key(line)] = true
+    }
+    marked := make([]string, len(lines))
+    differ := 0
+    for i, line := range lines {
+        op := diffEqual
+        if !known[key(line)] {
+            op = diffChange
+            differ++
+        }
+        marked[i] = diffGutters[op] + line
+    }
+    return marked, differ, nil
+}
+
+// compareFooter returns the footer counting differ of total lines that
+// differ, after the footer given by the user.
+func compareFooter(footer string, differ, total int) string {
+    count := fmt.Sprintf("%d of %d lines differ", differ, total)
+    if footer == "" {
+        return count
+    }
+    return footer + ", " + count
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestCompareLines(t *testing.T) {
+    golden := filepath.Join(t.TempDir(), "golden")
+    if err := os.WriteFile(golden, []byte("port = 80\nhost  = x\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    lines := []string{"host = x", "port = 80", "log = on"}
+    for _, tt := range []struct {
+        ignoreSpace bool
+        want        []string
+        differ      int
+    }{
+        {false, []string{"! host = x", "  port = 80", "! log = on"}, 2},
+        {true, []string{"  host = x", "  port = 80", "! log = on"}, 1},
+    } {
+        got, differ, err := compareLines(lines, golden, tt.ignoreSpace, true)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if !reflect.DeepEqual(got, tt.want) || differ != tt.differ {
+            t.Errorf("ignoreSpace %v: %q with %d differing, want %q with %d", tt.ignoreSpace, got, differ, tt.want, tt.differ)
+        }
+    }
+
+    if _, _, err := compareLines(lines, filepath.Join(t.TempDir(), "missing"), false, true); exitCode(err) != exitInput {
+        t.Errorf("comparing with a missing file: %v, want an input error", err)
+    }
+    if got, want := compareFooter("build 7", 1, 3), "build 7, 1 of 3 lines differ"; got != want {
+        t.Errorf("compareFooter = %q, want %q", got, want)
+    }
+}
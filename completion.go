@@ -0,0 +1,183 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+// completionShells are the shells completion scripts can be generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionCommand declares the options of the completion command which
+// prints a completion script for the given shell.
+func completionCommand(o *optionSet) func(args []string) error {
+    o.synopsis = strings.Join(completionShells, "|")
+
+    return func(args []string) error {
+        if len(args) != 1 {
+            o.fs.Usage()
+            return fmt.Errorf("completion: expected one of %s", o.synopsis)
+        }
+        switch args[0] {
+        case "bash":
+            writeBashCompletion(os.Stdout, o.prog)
+        case "zsh":
+            fmt.Fprintf(os.Stdout, "#compdef %s\n\nautoload -U +X bashcompinit && bashcompinit\n\n", o.prog)
+            writeBashCompletion(os.Stdout, o.prog)
+        case "fish":
+            writeFishCompletion(os.Stdout, o.prog)
+        default:
+            return fmt.Errorf("completion: unknown shell %q", args[0])
+        }
+        return nil
+    }
+}
+
+// completionEntry is a command together with its declared options.
+type completionEntry struct {
+    name string
+    o    *optionSet
+}
+
+// completionEntries returns every command with its options, sorted by name.
+// The options come from the same declarations used when running a command.
+func completionEntries(prog string) []completionEntry {
+    var entries []completionEntry
+    for name := range commands {
+        o, _ := commandOptions(prog, name)
+        entries = append(entries, completionEntry{name: name, o: o})
+    }
+    sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+    return entries
+}
+
+// commandNames returns the sorted command names.
+func commandNames(entries []completionEntry) string {
+    names := make([]string, len(entries))
+    for i, e := range entries {
+        names[i] = e.name
+    }
+    return strings.Join(names, " ")
+}
+
+// optionNames returns the spellings of an option.
+func optionNames(spec *optionSpec) []string {
+    names := []string{"--" + spec.long}
+    if spec.short != "" {
+        names = append([]string{"-" + spec.short}, names...)
+    }
+    return names
+}
+
+// writeBashCompletion writes a bash completion script for prog.
+func writeBashCompletion(w io.Writer, prog string) {
+    entries := completionEntries(prog)
+    fn := "_" + regexp.MustCompile(`\W`).ReplaceAllString(prog, "_") + "_complete"
+
+    fmt.Fprintf(w, "%s() {\n", fn)
+    fmt.Fprintf(w, "    local cur prev cmd words\n")
+    fmt.Fprintf(w, "    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+    fmt.Fprintf(w, "    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+    fmt.Fprintf(w, "    cmd=box\n")
+    fmt.Fprintf(w, "    case \"${COMP_WORDS[1]}\" in\n")
+    fmt.Fprintf(w, "        %s) cmd=\"${COMP_WORDS[1]}\" ;;\n", strings.ReplaceAll(commandNames(entries), " ", "|"))
+    fmt.Fprintf(w, "    esac\n")
+
+    // Option values.
+    fmt.Fprintf(w, "    case \"$cmd $prev\" in\n")
+    for _, e := range entries {
+        for _, spec := range e.o.specs {
+            if e.o.isBool(spec) {
+                continue
+            }
+            var patterns []string
+            for _, name := range optionNames(spec) {
+                patterns = append(patterns, fmt.Sprintf("\"%s %s\"", e.name, name))
+            }
+            values := strings.Join(spec.choices, " ")
+            if spec.dynamic != "" {
+                values += fmt.Sprintf(" $(%s %s 2>/dev/null)", prog, spec.dynamic)
+            }
+            if values == "" {
+                fmt.Fprintf(w, "        %s) return ;;\n", strings.Join(patterns, "|"))
+                continue
+            }
+            fmt.Fprintf(w, "        %s)\n", strings.Join(patterns, "|"))
+            fmt.Fprintf(w, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", values)
+            fmt.Fprintf(w, "            return ;;\n")
+        }
+    }
+    fmt.Fprintf(w, "    esac\n")
+
+    // Options and arguments of the command.
+    fmt.Fprintf(w, "    case \"$cmd\" in\n")
+    for _, e := range entries {
+        var words []string
+        for _, spec := range e.o.specs {
+            words = append(words, optionNames(spec)...)
+        }
+        words = append(words, commands[e.name].args...)
+        fmt.Fprintf(w, "        %s) words=\"%s\" ;;\n", e.name, strings.Join(words, " "))
+    }
+    fmt.Fprintf(w, "    esac\n")
+    fmt.Fprintf(w, "    if [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+    fmt.Fprintf(w, "        words=\"$words %s\"\n", commandNames(entries))
+    fmt.Fprintf(w, "    fi\n")
+    fmt.Fprintf(w, "    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+    fmt.Fprintf(w, "}\n")
+    fmt.Fprintf(w, "complete -o default -F %s %s\n", fn, prog)
+}
+
+// writeFishCompletion writes a fish completion script for prog.
+func writeFishCompletion(w io.Writer, prog string) {
+    entries := completionEntries(prog)
+    var others []string
+    for _, e := range entries {
+        if e.name != "box" {
+            others = append(others, e.name)
+        }
+    }
+
+    fmt.Fprintf(w, "complete -c %s -n '__fish_use_subcommand' -f -a '%s'\n", prog, commandNames(entries))
+    for _, e := range entries {
+        cond := "__fish_seen_subcommand_from " + e.name
+        if e.name == "box" {
+            cond = "not __fish_seen_subcommand_from " + strings.Join(others, " ")
+        }
+        for _, spec := range e.o.specs {
+            line := fmt.Sprintf("complete -c %s -n '%s'", prog, cond)
+            switch {
+            case len(spec.short) == 1:
+                line += " -s " + spec.short
+            case spec.short != "":
+                line += " -o " + spec.short
+            }
+            line += fmt.Sprintf(" -l %s -d %s", spec.long, fishQuote(spec.usage))
+            if !e.o.isBool(spec) {
+                values := strings.Join(spec.choices, " ")
+                if spec.dynamic != "" {
+                    values += fmt.Sprintf(" (%s %s 2>/dev/null)", prog, spec.dynamic)
+                }
+                if values != "" {
+                    line += " -x -a " + fishQuote(strings.TrimSpace(values))
+                } else {
+                    line += " -r"
+                }
+            }
+            fmt.Fprintln(w, line)
+        }
+        if args := commands[e.name].args; len(args) > 0 {
+            fmt.Fprintf(w, "complete -c %s -n '%s' -f -a '%s'\n", prog, cond, strings.Join(args, " "))
+        }
+    }
+}
+
+// fishQuote single-quotes s for fish. Fish still expands command
+// substitutions in the -a argument when completing.
+func fishQuote(s string) string {
+    return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+    "encoding"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+
+    "box/textbox"
+)
+
+// rcFile is the per-directory configuration file.
+const rcFile = ".textbox.toml"
+
+// userConfigPath returns the user configuration file.
+func userConfigPath() (string, error) {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "textbox", "config.toml"), nil
+}
+
+// configFiles returns the configuration files in increasing precedence: the
+// user configuration, the per-directory rc file and the file named with
+// --config. Only the explicit file has to exist.
+func configFiles(explicit string) []string {
+    var files []string
+    if path, err := userConfigPath(); err == nil {
+        files = append(files, path)
+    }
+    files = append(files, rcFile)
+    if explicit != "" {
+        files = append(files, explicit)
+    }
+    return files
+}
+
+// applyConfig layers the configuration files over the built-in defaults in
+// *opts and applies the options given on the command line or in the
+// environment on top, so the precedence is: command line, environment,
+// --config file, rc file, user configuration, built-in defaults.
+func applyConfig(o *optionSet, explicit string, opts *textbox.Options) error {
+    set := make(map[string]string)
+    sources := make(map[string]string)
+    o.fs.Visit(func(f *flag.Flag) {
+        // Repeated options keep their values; setting them again would
+        // add the values twice.
+        if _, ok := f.Value.(interface{ repeatable() }); ok {
+            return
+        }
+        set[f.Name] = f.Value.String()
+        sources[f.Name] = o.sources[o.longName(f.Name)]
+    })
+
+    loaded := *opts
+    for _, path := range configFiles(explicit) {
+        keys, err := loaded.Load(path)
+        if errors.Is(err, os.ErrNotExist) && path != explicit {
+            continue
+        } else if err != nil {
+            return err
+        }
+        for _, key := range keys {
+            o.sources[optionName(key)] = "config " + path
+        }
+    }
+    *opts = loaded
+
+    for name, value := range set {
+        if err := o.fs.Set(name, value); err != nil {
+            return err
+        }
+        o.sources[o.longName(name)] = sources[name]
+    }
+    return nil
+}
+
+// configFields calls fn with the key and value of every Options field, in
+// declaration order.
+func configFields(opts textbox.Options, fn func(key string, value reflect.Value)) {
+    v := reflect.ValueOf(opts)
+    for i := 0; i < v.NumField(); i++ {
+        if key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ","); key != "" && key != "-" {
+            fn(key, v.Field(i))
+        }
+    }
+}
+
+// configOptions maps the configuration keys whose options are named
+// differently, or set several keys, to the long option names.
+var configOptions = map[string]string{
+    "column_align":      "col-align",
+    "column_max_width":  "col-max-width",
+    "column_max_widths": "col-max-width",
+    "table_header":      "header",
+}
+
+// optionName returns the long option name of a configuration key.
+func optionName(key string) string {
+    return strings.ReplaceAll(key, "_", "-")
+}
+
+// configCommand declares the options of the config command.
+func configCommand(o *optionSet) func(args []string) error {
+    var force bool
+    o.synopsis = "init [--force] | show"
+    o.Bool(&force, "", "force", false, "Configuration", "Overwrite an existing configuration file")
+    o.commandLineOnly("force")
+
+    return func(args []string) error {
+        if len(args) != 1 {
+            o.fs.Usage()
+            return errors.New("config: expected init or show")
+        }
+        switch args[0] {
+        case "init":
+            return initConfig(o.prog, force)
+        case "show":
+            return showConfig(os.Stdout, o.prog)
+        }
+        return fmt.Errorf("config: unknown action %q", args[0])
+    }
+}
+
+// initConfig writes a commented example configuration to the user
+// configuration file.
+func initConfig(prog string, force bool) error {
+    path, err := userConfigPath()
+    if err != nil {
+        return err
+    }
+    flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+    if !force {
+        flags |= os.O_EXCL
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    f, err := os.OpenFile(path, flags, 0o644)
+    if errors.Is(err, os.ErrExist) {
+        return fmt.Errorf("config init: %s exists, use --force to overwrite it", path)
+    } else if err != nil {
+        return err
+    }
+
+    // Keys used only by the table command are documented by its options.
+    o, _ := commandOptions(prog, "box")
+    table, _ := commandOptions(prog, "table")
+    fmt.Fprintf(f, "# %s configuration.\n", prog)
+    fmt.Fprintf(f, "#\n# Options are applied in this order, later ones winning: built-in defaults,\n")
+    fmt.Fprintf(f, "# this file, %s in the current directory, --config FILE, environment,\n# command line.\n", rcFile)
+    configFields(textbox.DefaultOptions(), func(key string, value reflect.Value) {
+        name, ok := configOptions[key]
+        if !ok {
+            name = optionName(key)
+        }
+        usage := ""
+        if fl := o.fs.Lookup(name); fl != nil {
+            usage = fl.Usage
+        } else if fl := table.fs.Lookup(name); fl != nil {
+            usage = fl.Usage
+        }
+        fmt.Fprintf(f, "\n# %s\n# %s = %s\n", usage, key, tomlValue(value))
+    })
+    if err := f.Close(); err != nil {
+        return err
+    }
+    _, err = fmt.Fprintln(os.Stdout, "wrote", path)
+    return err
+}
+
+// showConfig prints the effective configuration of the box command together
+// with the source of every value.
+func showConfig(w io.Writer, prog string) error {
+    o, _ := commandOptions(prog, "box")
+    if err := o.Parse(nil); err != nil {
+        return err
+    }
+    opts := textbox.DefaultOptions()
+    if err := applyConfig(o, "", &opts); err != nil {
+        return err
+    }
+    configFields(opts, func(key string, value reflect.Value) {
+        source := o.sources[optionName(key)]
+        if source == "" {
+            source = "default"
+        }
+        if strings.HasPrefix(source, "env ") {
+            flagValue := o.fs.Lookup(optionName(key)).Value
+            if g, ok := flagValue.(flag.Getter); ok {
+                value = reflect.ValueOf(g.Get())
+            } else {
+                value = reflect.ValueOf(flagValue)
+            }
+        }
+        fmt.Fprintf(w, "%s = %s # %s\n", key, tomlValue(value), source)
+    })
+    return nil
+}
+
+// tomlValue formats a configuration value as TOML, slices as arrays and
+// maps as inline tables with sorted keys.
+func tomlValue(v reflect.Value) string {
+    if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+        text, _ := m.MarshalText()
+        return strconv.Quote(string(text))
+    }
+    switch v.Kind() {
+    case reflect.String:
+        return strconv.Quote(v.String())
+    case reflect.Slice:
+        items := make([]string, v.Len())
+        for i := range items {
+            items[i] = tomlValue(v.Index(i))
+        }
+        return "[" + strings.Join(items, ", ") + "]"
+    case reflect.Map:
+        keys := v.MapKeys()
+        sort.Slice(keys, func(i, j int) bool {
+            if keys[i].CanInt() {
+                return keys[i].Int() < keys[j].Int()
+            }
+            return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+        })
+        items := make([]string, len(keys))
+        for i, key := range keys {
+            items[i] = fmt.Sprintf("%v = %s", key, tomlValue(v.MapIndex(key)))
+        }
+        return "{" + strings.Join(items, ", ") + "}"
+    }
+    return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "box/textbox"
+)
+
+func TestInitConfigLoads(t *testing.T) {
+    t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+    t.Setenv("HOME", t.TempDir())
+    if err := initConfig("box", false); err != nil {
+        t.Fatal(err)
+    }
+    path, err := userConfigPath()
+    if err != nil {
+        t.Fatal(err)
+    }
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Every example line loads once uncommented, and every key is
+    // described.
+    var uncommented []string
+    for _, line := range strings.Split(string(data), "\n") {
+        if line == "# " {
+            t.Error("config init wrote a key without a description")
+        }
+        if strings.HasPrefix(line, "# ") && strings.Contains(line, " = ") {
+            line = strings.TrimPrefix(line, "# ")
+        }
+        uncommented = append(uncommented, line)
+    }
+    loaded := filepath.Join(t.TempDir(), "config.toml")
+    if err := os.WriteFile(loaded, []byte(strings.Join(uncommented, "\n")), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    opts := textbox.DefaultOptions()
+    if _, err := opts.Load(loaded); err != nil {
+        t.Errorf("loading the uncommented configuration: %v", err)
+    }
+}
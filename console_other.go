@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+// setupConsole reports whether the terminal can show box drawing
+// characters. Outside Windows, output is UTF-8.
+func setupConsole() bool {
+    return true
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package main
+
+import (
+    "os"
+
+    "golang.org/x/sys/windows"
+)
+
+// utf8CodePage is the Windows code page number of UTF-8.
+const utf8CodePage = 65001
+
+// setupConsole enables escape sequences and UTF-8 output on the console
+// stdout is connected to. It reports whether the console can show box
+// drawing characters; legacy consoles without escape sequence support and
+// consoles stuck on another code page cannot. Redirected output is always
+// written as UTF-8.
+func setupConsole() bool {
+    h := windows.Handle(os.Stdout.Fd())
+    var mode uint32
+    if err := windows.GetConsoleMode(h, &mode); err != nil {
+        return true
+    }
+    if err := windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+        return false
+    }
+    if cp, err := windows.GetConsoleOutputCP(); err == nil && cp == utf8CodePage {
+        return true
+    }
+    return windows.SetConsoleOutputCP(utf8CodePage) == nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+
+    "box/textbox"
+)
+
+// debugf writes one diagnostic line to stderr.
+func debugf(format string, args ...any) {
+    fmt.Fprintf(os.Stderr, "debug: "+format+"\n", args...)
+}
+
+// debugOptions reports the value of every option and where it was set.
+func debugOptions(o *optionSet) {
+    for _, spec := range o.specs {
+        source := o.sources[spec.long]
+        if source == "" {
+            source = "default"
+        }
+        debugf("option --%s=%q (%s)", spec.long, o.fs.Lookup(spec.long).Value.String(), source)
+    }
+}
+
+// debugTerminal reports the size of the terminal stdout is connected to.
+func debugTerminal() {
+    width, height, err := termSize()
+    if err != nil {
+        debugf("terminal: not detected (%v)", err)
+        return
+    }
+    debugf("terminal: %dx%d", width, height)
+}
+
+// writeRuler writes a ruler numbering the columns of the box framing lines,
+// starting at column 0: the tens above the units.
+func writeRuler(w io.Writer, lines []string, opts textbox.Options) error {
+    d, err := textbox.Measure(lines, textbox.WithOptions(opts))
+    if err != nil {
+        return err
+    }
+    var tens, units strings.Builder
+    for col := 0; col < d.Width; col++ {
+        if col%10 == 0 {
+            tens.WriteString(strconv.Itoa(col / 10 % 10))
+        } else {
+            tens.WriteByte(' ')
+        }
+        units.WriteString(strconv.Itoa(col % 10))
+    }
+    _, err = fmt.Fprintf(w, "%s\n%s\n", strings.TrimRight(tens.String(), " "), units.String())
+    return err
+}
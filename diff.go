@@ -0,0 +1,173 @@
+package main
+
+import (
+    "io"
+    "os"
+    "regexp"
+
+    "box/textbox"
+)
+
+// diffOp is how a row of a diff pairs the lines of two inputs.
+type diffOp int
+
+// Diff operations.
+const (
+    // diffEqual pairs a line found in both inputs.
+    diffEqual diffOp = iota
+    // diffDelete is a line only in the first input.
+    diffDelete
+    // diffInsert is a line only in the second input.
+    diffInsert
+    // diffChange pairs a line of the first input replaced by one of the
+    // second.
+    diffChange
+)
+
+// diffRow is one row of a side-by-side diff.
+type diffRow struct {
+    op          diffOp
+    left, right string
+}
+
+// diffGutters start the lines of the diff boxes, indexed by operation.
+var diffGutters = []string{diffEqual: "  ", diffDelete: "- ", diffInsert: "+ ", diffChange: "! "}
+
+// diffGap is the number of columns between the two boxes of a diff.
+const diffGap = 2
+
+// diffLines pairs the lines of a and b along their longest common
+// subsequence. Deleted and inserted lines between two common lines are
+// paired up as changed lines as far as they go.
+func diffLines(a, b []string) []diffRow {
+    // lcs[i][j] is the length of the longest common subsequence of a[i:]
+    // and b[j:].
+    lcs := make([][]int, len(a)+1)
+    for i := range lcs {
+        lcs[i] = make([]int, len(b)+1)
+    }
+    for i := len(a) - 1; i >= 0; i-- {
+        for j := len(b) - 1; j >= 0; j-- {
+            if a[i] == b[j] {
+                lcs[i][j] = lcs[i+1][j+1] + 1
+            } else {
+                lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+            }
+        }
+    }
+
+    var rows []diffRow
+    var deleted, inserted []string
+    flush := func() {
+        for k := 0; k < max(len(deleted), len(inserted)); k++ {
+            switch {
+            case k < len(deleted) && k < len(inserted):
+                rows = append(rows, diffRow{diffChange, deleted[k], inserted[k]})
+            case k < len(deleted):
+                rows = append(rows, diffRow{op: diffDelete, left: deleted[k]})
+            default:
+                rows = append(rows, diffRow{op: diffInsert, right: inserted[k]})
+            }
+        }
+        deleted, inserted = nil, nil
+    }
+    i, j := 0, 0
+    for i < len(a) || j < len(b) {
+        switch {
+        case i < len(a) && j < len(b) && a[i] == b[j]:
+            flush()
+            rows = append(rows, diffRow{diffEqual, a[i], b[j]})
+            i, j = i+1, j+1
+        case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+            deleted = append(deleted, a[i])
+            i++
+        default:
+            inserted = append(inserted, b[j])
+            j++
+        }
+    }
+    flush()
+    return rows
+}
+
+// diffColors are the highlight rules coloring the gutters of changed rows.
+var diffColors = []struct {
+    pattern string
+    color   string
+}{
+    {`^- `, "red"},
+    {`^\+ `, "green"},
+    {`^! `, "yellow"},
+}
+
+// appendDiffColors appends the rules of diffColors to rules, after those
+// given by the user.
+func appendDiffColors(rules []textbox.HighlightRule) ([]textbox.HighlightRule, error) {
+    for _, c := range diffColors {
+        color, err := textbox.ParseANSIColor(c.color)
+        if err != nil {
+            return nil, err
+        }
+        rules = append(rules, textbox.HighlightRule{Pattern: regexp.MustCompile(c.pattern), Color: color})
+    }
+    return rules, nil
+}
+
+// renderDiff writes the lines of the files pathA and pathB in two boxes side
+// by side, paired by diffLines. A line missing on one side leaves a blank
+// row there. The boxes have the same width and, without a title, the file
+// names as titles.
+func renderDiff(w io.Writer, pathA, pathB string, normalize bool, opts textbox.Options) error {
+    a, err := readFile(pathA, normalize)
+    if err != nil {
+        return err
+    }
+    b, err := readFile(pathB, normalize)
+    if err != nil {
+        return err
+    }
+    var left, right []string
+    for _, row := range diffLines(a, b) {
+        l, r := "", ""
+        if row.op != diffInsert {
+            l = diffGutters[row.op] + row.left
+        }
+        if row.op != diffDelete {
+            r = diffGutters[row.op] + row.right
+        }
+        left, right = append(left, l), append(right, r)
+    }
+
+    if opts.HighlightRules, err = appendDiffColors(opts.HighlightRules); err != nil {
+        return err
+    }
+    leftOpts, rightOpts := opts, opts
+    if opts.Title == "" {
+        leftOpts.Title, rightOpts.Title = pathA, pathB
+    }
+    if opts.Width <= 0 {
+        // Both boxes are drawn as wide as the wider one.
+        l, err := textbox.Measure(left, textbox.WithOptions(leftOpts))
+        if err != nil {
+            return err
+        }
+        r, err := textbox.Measure(right, textbox.WithOptions(rightOpts))
+        if err != nil {
+            return err
+        }
+        leftOpts.Width, rightOpts.Width = max(l.Width, r.Width), max(l.Width, r.Width)
+    }
+    return textbox.Join(w, textbox.Horizontal, diffGap,
+        textbox.NewBox(left, textbox.WithOptions(leftOpts)),
+        textbox.NewBox(right, textbox.WithOptions(rightOpts)))
+}
+
+// readFile reads the lines of the file path like readLines.
+func readFile(path string, normalize bool) ([]string, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return readLines(f, path, normalize)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+
+    "box/textbox"
+)
+
+func TestDiffLines(t *testing.T) {
+    tests := []struct {
+        a, b []string
+        want []diffRow
+    }{
+        {[]string{"a", "b"}, []string{"a", "b"}, []diffRow{{diffEqual, "a", "a"}, {diffEqual, "b", "b"}}},
+        {[]string{"a", "b", "c"}, []string{"a", "c"}, []diffRow{{diffEqual, "a", "a"}, {op: diffDelete, left: "b"}, {diffEqual, "c", "c"}}},
+        {[]string{"a"}, []string{"x", "a"}, []diffRow{{op: diffInsert, right: "x"}, {diffEqual, "a", "a"}}},
+        {[]string{"a", "b", "c"}, []string{"a", "B", "C", "D"}, []diffRow{
+            {diffEqual, "a", "a"}, {diffChange, "b", "B"}, {diffChange, "c", "C"}, {op: diffInsert, right: "D"},
+        }},
+        {nil, nil, nil},
+    }
+    for _, tt := range tests {
+        if got := diffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("diffLines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+        }
+    }
+}
+
+func TestRenderDiff(t *testing.T) {
+    dir := t.TempDir()
+    a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+    if err := os.WriteFile(a, []byte("port = 80\nhost = x\nlog = on\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(b, []byte("port = 8080\nhost = x\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    opts := textbox.DefaultOptions()
+    opts.NoColor, opts.Title = true, "config"
+    var buf strings.Builder
+    if err := renderDiff(&buf, a, b, true, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := `┌──┘ config └───┐  ┌──┘ config └───┐
+│ ! port = 80   │  │ ! port = 8080 │
+│   host = x    │  │   host = x    │
+│ - log = on    │  │               │
+└───────────────┘  └───────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("diff:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    if err := renderDiff(&buf, a, filepath.Join(dir, "missing"), true, opts); exitCode(err) != exitInput {
+        t.Errorf("diff with a missing file: %v, want an input error", err)
+    }
+}
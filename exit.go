@@ -0,0 +1,60 @@
+package main
+
+import (
+    "errors"
+    "io/fs"
+    "syscall"
+)
+
+// Exit statuses of box. Scripts may depend on them; see the README.
+const (
+    exitOK = 0
+    // exitUsage follows invalid options, arguments and configuration.
+    exitUsage = 1
+    // exitInput follows input that cannot be opened or read in full.
+    exitInput = 2
+    // exitOutput follows output that cannot be written.
+    exitOutput = 3
+    // exitInterrupted follows an interruption by SIGINT, the status of a
+    // process killed by it; SIGTERM gives 143 the same way.
+    exitInterrupted = 130
+    // exitBrokenPipe follows the reader of stdout going away, the status a
+    // shell reports for a process killed by SIGPIPE.
+    exitBrokenPipe = 141
+)
+
+// reportedError is an error the user has already been shown, such as an
+// unknown flag the flag package printed with the usage.
+type reportedError struct {
+    err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// exitCode returns the exit status for err, returned by a command. Failing
+// file operations tell input from output errors; everything else is a usage
+// error.
+func exitCode(err error) int {
+    var pathErr *fs.PathError
+    var readErr *readError
+    var interrupted *errInterrupted
+    switch {
+    case err == nil:
+        return exitOK
+    case isBrokenPipe(err):
+        return exitBrokenPipe
+    case errors.As(err, &interrupted):
+        if sig, ok := interrupted.signal.(syscall.Signal); ok {
+            return 128 + int(sig)
+        }
+        return exitInterrupted
+    case errors.As(err, &readErr):
+        return exitInput
+    case errors.As(err, &pathErr) && pathErr.Op == "write":
+        return exitOutput
+    case errors.As(err, &pathErr) && (pathErr.Op == "open" || pathErr.Op == "read"):
+        return exitInput
+    }
+    return exitUsage
+}
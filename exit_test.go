@@ -0,0 +1,170 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "path/filepath"
+    "runtime"
+    "strings"
+    "syscall"
+    "testing"
+)
+
+// runWith runs box with args, reading input from stdin and writing the
+// output to stdout, and returns the exit status and what went to stderr.
+func runWith(t *testing.T, input string, stdout *os.File, args ...string) (int, string) {
+    t.Helper()
+    in := filepath.Join(t.TempDir(), "in")
+    if err := os.WriteFile(in, []byte(input), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    stdin, err := os.Open(in)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer stdin.Close()
+    oldStdin, oldStdout := os.Stdin, os.Stdout
+    os.Stdin, os.Stdout = stdin, stdout
+    defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+
+    var stderr strings.Builder
+    code := run(append([]string{"box"}, args...), &stderr)
+    return code, stderr.String()
+}
+
+func TestRunExitCodes(t *testing.T) {
+    out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer out.Close()
+    missing := filepath.Join(t.TempDir(), "missing")
+
+    tests := []struct {
+        name string
+        args []string
+        want int
+    }{
+        {"success", nil, exitOK},
+        {"help", []string{"--help"}, exitOK},
+        {"unknown flag", []string{"--no-such-flag"}, exitUsage},
+        {"invalid value", []string{"--progress", "2"}, exitUsage},
+        {"unknown command action", []string{"styles", "frobnicate"}, exitUsage},
+        {"missing beside file", []string{"--width", "20", "--beside", missing}, exitInput},
+        {"missing config file", []string{"--config", missing}, exitInput},
+    }
+    for _, tt := range tests {
+        if code, stderr := runWith(t, "hello\n", out, tt.args...); code != tt.want {
+            t.Errorf("%s: exit status %d, want %d (stderr %q)", tt.name, code, tt.want, stderr)
+        }
+    }
+
+    // Errors are reported once, flag errors with the usage.
+    _, stderr := runWith(t, "", out, "--no-such-flag")
+    if n := strings.Count(stderr, "no-such-flag"); n != 1 {
+        t.Errorf("unknown flag reported %d times:\n%s", n, stderr)
+    }
+}
+
+func TestRunOutputErrors(t *testing.T) {
+    full, err := os.OpenFile("/dev/full", os.O_WRONLY, 0)
+    if err != nil {
+        t.Skip(err)
+    }
+    defer full.Close()
+    if code, stderr := runWith(t, "hello\n", full); code != exitOutput {
+        t.Errorf("writing to a full device: exit status %d, want %d (stderr %q)", code, exitOutput, stderr)
+    }
+
+    ignoreBrokenPipe()
+    r, w, err := os.Pipe()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer w.Close()
+    r.Close()
+    if code, stderr := runWith(t, "hello\n", w); code != exitBrokenPipe || stderr != "" {
+        t.Errorf("writing to a closed pipe: exit status %d and stderr %q, want %d quietly", code, stderr, exitBrokenPipe)
+    }
+}
+
+func TestRunPartialOnError(t *testing.T) {
+    out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer out.Close()
+    // A line longer than the scanner buffer fails the read.
+    input := "first\n" + strings.Repeat("x", 1<<17) + "\n"
+
+    code, stderr := runWith(t, input, out)
+    if code != exitInput || !strings.Contains(stderr, "after 1 lines") {
+        t.Errorf("read error: exit status %d and stderr %q, want %d", code, stderr, exitInput)
+    }
+    if info, _ := out.Stat(); info.Size() != 0 {
+        t.Errorf("read error without --partial-on-error drew %d bytes", info.Size())
+    }
+
+    code, _ = runWith(t, input, out, "--partial-on-error")
+    if code != exitInput {
+        t.Errorf("read error with --partial-on-error: exit status %d, want %d", code, exitInput)
+    }
+    drawn, err := os.ReadFile(out.Name())
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, want := range []string{"first", "[incomplete: read error after 1 lines]"} {
+        if !strings.Contains(string(drawn), want) {
+            t.Errorf("partial box lacks %q:\n%s", want, drawn)
+        }
+    }
+}
+
+func TestRunInputErrors(t *testing.T) {
+    out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer out.Close()
+    tests := []struct {
+        name, input string
+        args        []string
+    }{
+        {"bad csv", "a,\"b\nc\n", []string{"table"}},
+        {"empty input", "", []string{"--empty", "error"}},
+    }
+    for _, tt := range tests {
+        if code, stderr := runWith(t, tt.input, out, tt.args...); code != exitInput {
+            t.Errorf("%s: exit status %d, want %d (stderr %q)", tt.name, code, exitInput, stderr)
+        }
+    }
+}
+
+func TestExitCodeSignals(t *testing.T) {
+    if runtime.GOOS == "windows" {
+        t.Skip("signals cannot be sent to the own process")
+    }
+    self, err := os.FindProcess(os.Getpid())
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, tt := range []struct {
+        signal os.Signal
+        want   int
+    }{
+        {os.Interrupt, 130},
+        {syscall.SIGTERM, 143},
+    } {
+        ctx, stop := interruptContext()
+        if err := self.Signal(tt.signal); err != nil {
+            t.Fatal(err)
+        }
+        <-ctx.Done()
+        err := context.Cause(ctx)
+        stop()
+        if code := exitCode(fmt.Errorf("following stdin: %w", err)); code != tt.want {
+            t.Errorf("%v: exit status %d, want %d", tt.signal, code, tt.want)
+        }
+    }
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// optionSpec describes a single command line option.
+type optionSpec struct {
+    short string
+    long  string
+    group string
+    usage string
+
+    // choices and dynamic drive shell completion of the option value:
+    // choices are fixed candidates, dynamic are the arguments of a command
+    // printing further candidates at completion time.
+    choices []string
+    dynamic string
+
+    // noEnv keeps the option from being set by the environment.
+    noEnv bool
+
+    // aliases are former long names still accepted on the command line.
+    // They are left out of the usage and completion.
+    aliases []string
+}
+
+// optionSet declares every option exactly once with a short and a long name.
+// Both names are registered with the underlying flag.FlagSet and share the
+// same value, so -t x, --title x and --title=x are equivalent.
+type optionSet struct {
+    fs    *flag.FlagSet
+    specs []*optionSpec
+
+    // prog is the program name and synopsis describes the arguments in the
+    // usage line.
+    prog     string
+    synopsis string
+
+    // sources records where options not left at their default were set.
+    sources map[string]string
+}
+
+// newOptionSet creates an empty option set.
+func newOptionSet(name string) *optionSet {
+    o := &optionSet{
+        fs:       flag.NewFlagSet(name, flag.ContinueOnError),
+        synopsis: "[options] < input",
+        sources:  make(map[string]string),
+    }
+    o.fs.Usage = func() { o.printUsage(o.fs.Output()) }
+    return o
+}
+
+// register adds the spec and registers the short name as an alias of the
+// already registered long name.
+func (o *optionSet) register(short, long, group, usage string) {
+    if short != "" {
+        o.fs.Var(o.fs.Lookup(long).Value, short, usage)
+    }
+    o.specs = append(o.specs, &optionSpec{short: short, long: long, group: group, usage: usage})
+}
+
+func (o *optionSet) String(p *string, short, long, value, group, usage string) {
+    o.fs.StringVar(p, long, value, usage)
+    o.register(short, long, group, usage)
+}
+
+func (o *optionSet) Int(p *int, short, long string, value int, group, usage string) {
+    o.fs.IntVar(p, long, value, usage)
+    o.register(short, long, group, usage)
+}
+
+func (o *optionSet) Float(p *float64, short, long string, value float64, group, usage string) {
+    o.fs.Float64Var(p, long, value, usage)
+    o.register(short, long, group, usage)
+}
+
+func (o *optionSet) Bool(p *bool, short, long string, value bool, group, usage string) {
+    o.fs.BoolVar(p, long, value, usage)
+    o.register(short, long, group, usage)
+}
+
+func (o *optionSet) Var(value flag.Value, short, long, group, usage string) {
+    o.fs.Var(value, long, usage)
+    o.register(short, long, group, usage)
+}
+
+// complete attaches completion candidates to the option named long.
+func (o *optionSet) complete(long, dynamic string, choices ...string) {
+    for _, spec := range o.specs {
+        if spec.long == long {
+            spec.choices, spec.dynamic = choices, dynamic
+        }
+    }
+}
+
+// commandLineOnly excludes the options named long from the environment. It
+// is meant for informational and configuration options: a stray
+// TEXTBOX_VERSION would print the version on every run.
+func (o *optionSet) commandLineOnly(long ...string) {
+    for _, spec := range o.specs {
+        for _, name := range long {
+            if spec.long == name {
+                spec.noEnv = true
+            }
+        }
+    }
+}
+
+// alias keeps accepting the former names of the option named long.
+func (o *optionSet) alias(long string, names ...string) {
+    for _, spec := range o.specs {
+        if spec.long == long {
+            for _, name := range names {
+                o.fs.Var(o.fs.Lookup(long).Value, name, spec.usage)
+            }
+            spec.aliases = append(spec.aliases, names...)
+        }
+    }
+}
+
+// isBool reports whether spec is a flag without a value.
+func (o *optionSet) isBool(spec *optionSpec) bool {
+    b, ok := o.fs.Lookup(spec.long).Value.(interface{ IsBoolFlag() bool })
+    return ok && b.IsBoolFlag()
+}
+
+// Parse parses the arguments and then defaults the options not given on the
+// command line from the environment.
+func (o *optionSet) Parse(args []string) error {
+    if err := o.fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+        return err
+    } else if err != nil {
+        // The flag package has printed the error and the usage.
+        return &reportedError{err}
+    }
+    o.fs.Visit(func(f *flag.Flag) { o.sources[o.longName(f.Name)] = "command line" })
+    return o.parseEnv()
+}
+
+// envName returns the environment variable defaulting the option named long,
+// TEXTBOX_FOO_BAR for --foo-bar.
+func envName(long string) string {
+    return "TEXTBOX_" + strings.ToUpper(strings.ReplaceAll(long, "-", "_"))
+}
+
+// parseEnv sets the options not given on the command line from their
+// environment variables.
+func (o *optionSet) parseEnv() error {
+    for _, spec := range o.specs {
+        if spec.noEnv {
+            continue
+        }
+        value, ok := os.LookupEnv(envName(spec.long))
+        if !ok || o.sources[spec.long] != "" {
+            continue
+        }
+        if err := o.fs.Set(spec.long, value); err != nil {
+            return fmt.Errorf("invalid value %q for %s: %v", value, envName(spec.long), err)
+        }
+        o.sources[spec.long] = "env " + envName(spec.long)
+    }
+    return nil
+}
+
+// longName returns the long name of the option registered as name.
+func (o *optionSet) longName(name string) string {
+    for _, spec := range o.specs {
+        if spec.short == name {
+            return spec.long
+        }
+        for _, alias := range spec.aliases {
+            if alias == name {
+                return spec.long
+            }
+        }
+    }
+    return name
+}
+
+// printUsage writes the option list grouped in the order the groups were
+// first used.
+func (o *optionSet) printUsage(w io.Writer) {
+    fmt.Fprintf(w, "Usage: %s %s\n", o.fs.Name(), o.synopsis)
+    for _, group := range o.groups() {
+        fmt.Fprintf(w, "\n%s:\n", group)
+        for _, spec := range o.specs {
+            if spec.group != group {
+                continue
+            }
+            fmt.Fprintln(w, o.usageLine(spec))
+        }
+    }
+}
+
+// usageLine formats one option as "  -t, --title string   Box title".
+func (o *optionSet) usageLine(spec *optionSpec) string {
+    fl := o.fs.Lookup(spec.long)
+    argName, usage := flag.UnquoteUsage(fl)
+    if argName == "value" {
+        // Options declared with Var take a name, such as an alignment.
+        argName = "string"
+    }
+
+    names := "    --" + spec.long
+    if spec.short != "" {
+        names = "-" + spec.short + ", --" + spec.long
+    }
+    if argName != "" {
+        names += " " + argName
+    }
+
+    var b strings.Builder
+    fmt.Fprintf(&b, "  %-24s %s", names, usage)
+    if fl.DefValue != "" && fl.DefValue != "false" && fl.DefValue != "0" {
+        fmt.Fprintf(&b, " (default %s)", fl.DefValue)
+    }
+    return b.String()
+}
+
+// groups returns the option groups in the order they were first used.
+func (o *optionSet) groups() []string {
+    var groups []string
+    seen := make(map[string]bool)
+    for _, spec := range o.specs {
+        if !seen[spec.group] {
+            seen[spec.group] = true
+            groups = append(groups, spec.group)
+        }
+    }
+    return groups
+}
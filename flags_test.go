@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestParseEnvSkipsCommandLineOnlyOptions(t *testing.T) {
+    t.Setenv("TEXTBOX_VERSION", "1")
+    t.Setenv("TEXTBOX_CONFIG", "missing.toml")
+    t.Setenv("TEXTBOX_LIST_STYLES", "1")
+    t.Setenv("TEXTBOX_TITLE", "from env")
+
+    o, _ := commandOptions("box", "box")
+    if err := o.Parse(nil); err != nil {
+        t.Fatal(err)
+    }
+    for _, name := range []string{"version", "config", "list-styles"} {
+        if source := o.sources[name]; source != "" {
+            t.Errorf("--%s set from %s, want the default", name, source)
+        }
+        if value := o.fs.Lookup(name).Value.String(); value != o.fs.Lookup(name).DefValue {
+            t.Errorf("--%s = %q, want the default", name, value)
+        }
+    }
+    if got := o.fs.Lookup("title").Value.String(); got != "from env" {
+        t.Errorf("--title = %q, want %q from TEXTBOX_TITLE", got, "from env")
+    }
+}
+
+func TestAlignContentAlias(t *testing.T) {
+    o, _ := commandOptions("box", "box")
+    if err := o.Parse([]string{"--align-content", "right"}); err != nil {
+        t.Fatal(err)
+    }
+    if got := o.fs.Lookup("align").Value.String(); got != "right" {
+        t.Errorf("--align = %q after --align-content right, want right", got)
+    }
+    if source := o.sources["align"]; source != "command line" {
+        t.Errorf("--align set from %q, want the command line", source)
+    }
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "errors"
+    "io"
+    "os"
+    "os/signal"
+    "syscall"
+
+    "box/textbox"
+)
+
+// errInterrupted is returned when box is interrupted by SIGINT or SIGTERM,
+// carrying the signal; the box command then exits with the status of a
+// process killed by it, 130 or 143.
+type errInterrupted struct {
+    signal os.Signal
+}
+
+func (e *errInterrupted) Error() string { return "interrupted" }
+
+// interruptContext returns a context canceled with an *errInterrupted as
+// its cause when box receives SIGINT or SIGTERM, and a function that stops
+// listening for them.
+func interruptContext() (context.Context, func()) {
+    ctx, cancel := context.WithCancelCause(context.Background())
+    signals := make(chan os.Signal, 1)
+    signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+    done := make(chan struct{})
+    go func() {
+        select {
+        case sig := <-signals:
+            cancel(&errInterrupted{signal: sig})
+        case <-done:
+        }
+    }()
+    return ctx, func() {
+        signal.Stop(signals)
+        close(done)
+        cancel(nil)
+    }
+}
+
+// followInput draws the lines of r into a box on w as they arrive, until r ends
+// or ctx is done. The bottom border is written in every case, so the box is
+// complete even when following is interrupted. normalize is as for
+// readLines. A cancelled ctx yields the ctx error.
+func followInput(ctx context.Context, r io.Reader, w io.Writer, normalize bool, opts textbox.Options) error {
+    if opts.Width <= 0 {
+        return errors.New("--follow needs --width")
+    }
+    s, err := textbox.NewStreamer(w, textbox.WithOptions(opts))
+    if err != nil {
+        return err
+    }
+
+    lines := make(chan string)
+    readErr := make(chan error, 1)
+    go func() {
+        var err error
+        // Every way out reports the read error, nil if none, before
+        // closing lines.
+        defer func() {
+            readErr <- err
+            close(lines)
+        }()
+        scanner := bufio.NewScanner(r)
+        if normalize {
+            scanner.Split(scanNewlines)
+        }
+        n := 0
+        for ; scanner.Scan(); n++ {
+            select {
+            case lines <- scanner.Text():
+            case <-ctx.Done():
+                return
+            }
+        }
+        if scanErr := scanner.Err(); scanErr != nil {
+            err = &readError{name: "stdin", lines: n, err: scanErr}
+        }
+    }()
+
+    for {
+        select {
+        case <-ctx.Done():
+            if err := s.Close(); err != nil {
+                return err
+            }
+            return ctx.Err()
+        case line, ok := <-lines:
+            if !ok {
+                // A failed read ends the box like the end of the input.
+                return errors.Join(s.Close(), <-readErr, ctx.Err())
+            }
+            if err := s.WriteLine(line); err != nil {
+                // Nothing more can be written to a closed pipe.
+                if !isBrokenPipe(err) {
+                    s.Close()
+                }
+                return err
+            }
+        }
+    }
+}
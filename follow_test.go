@@ -0,0 +1,52 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "io"
+    "strings"
+    "testing"
+
+    "box/textbox"
+)
+
+func TestFollowClosesBoxWhenCancelled(t *testing.T) {
+    r, w := io.Pipe()
+    defer w.Close()
+    opts := textbox.DefaultOptions()
+    opts.Width = 10
+
+    ctx, cancel := context.WithCancel(context.Background())
+    var out bytes.Buffer
+    done := make(chan error)
+    go func() { done <- followInput(ctx, r, &out, true, opts) }()
+    // The write returns once follow has taken the line.
+    io.WriteString(w, "one\n")
+    io.WriteString(w, "two\n")
+    cancel()
+
+    if err := <-done; !errors.Is(err, context.Canceled) {
+        t.Fatalf("followInput = %v, want %v", err, context.Canceled)
+    }
+    rows := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+    if got, want := rows[len(rows)-1], "└────────┘"; got != want {
+        t.Errorf("last row %q, want the bottom border %q", got, want)
+    }
+    if !strings.Contains(out.String(), "│ one    │") {
+        t.Errorf("output %q lacks the first line", out.String())
+    }
+}
+
+func TestFollowClosesBoxAtEndOfInput(t *testing.T) {
+    opts := textbox.DefaultOptions()
+    opts.Width = 10
+    var out bytes.Buffer
+    if err := followInput(context.Background(), strings.NewReader("one\r\ntwo\rthree"), &out, true, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := "┌────────┐\n│ one    │\n│ two    │\n│ three  │\n└────────┘\n"
+    if out.String() != want {
+        t.Errorf("output %q, want %q", out.String(), want)
+    }
+}
@@ -2,6 +2,13 @@ module box
 
 go 1.23.6
 
+require (
+	golang.org/x/sys v0.30.0
+	golang.org/x/term v0.29.0
+)
+
+require github.com/rivo/uniseg v0.4.7
+
 require github.com/mattn/go-runewidth v0.0.16
 
-require github.com/rivo/uniseg v0.2.0 // indirect
+require golang.org/x/text v0.22.0
@@ -0,0 +1,40 @@
+package main
+
+import "strings"
+
+// eastAsianCharsets are the multibyte charsets of CJK locales, in which
+// ambiguous characters are two columns wide.
+var eastAsianCharsets = map[string]bool{
+    "eucjp": true, "euckr": true, "euccn": true, "sjis": true, "jis": true,
+    "cp932": true, "cp936": true, "cp949": true, "cp950": true, "cp51932": true,
+    "big5": true, "gbk": true, "gb2312": true,
+}
+
+// localeAmbiguousWide reports whether the wcwidth of the locale name, such
+// as ja_JP.UTF-8, measures East Asian ambiguous characters two columns
+// wide. It follows the rules runewidth applies to LC_CTYPE: Chinese,
+// Japanese and Korean locales and CJK charsets are wide, a @cjk_narrow
+// modifier and the C locale narrow.
+func localeAmbiguousWide(name string) bool {
+    if name == "" || name == "C" || name == "POSIX" || strings.HasPrefix(name, "C.") {
+        return false
+    }
+    lang, charset, _ := strings.Cut(name, ".")
+    charset, modifier, _ := strings.Cut(strings.ToLower(charset), "@")
+    if modifier == "cjk_narrow" {
+        return false
+    }
+    charset = strings.ReplaceAll(charset, "-", "")
+    if eastAsianCharsets[charset] {
+        return true
+    }
+    if charset != "utf8" {
+        return false
+    }
+    for _, cjk := range []string{"ja", "ko", "zh"} {
+        if lang == cjk || strings.HasPrefix(lang, cjk+"_") {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+    "fmt"
+    "io"
+
+    "box/textbox"
+)
+
+// writeMetadata writes comment lines starting with prefix that describe the
+// box framing lines: its size, the columns of the border and the interior,
+// and the kind of every row, numbered from 1.
+func writeMetadata(w io.Writer, prefix string, lines []string, opts textbox.Options) error {
+    d, err := textbox.Measure(lines, textbox.WithOptions(opts))
+    if err != nil {
+        return err
+    }
+    left, right := d.BorderWidth, d.BorderWidth+d.InnerWidth
+    if _, err := fmt.Fprintf(w, "%sbox width=%d height=%d\n%scolumns border=%s interior=%s border=%s\n",
+        prefix, d.Width, d.Height,
+        prefix, columnRange(0, left), columnRange(left, right), columnRange(right, right+d.BorderWidth)); err != nil {
+        return err
+    }
+    for i, kind := range d.Rows {
+        if _, err := fmt.Fprintf(w, "%srow %d %s\n", prefix, i+1, kind); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// columnRange writes the columns from start up to end, counted from 0, as
+// start-last or a single column.
+func columnRange(start, end int) string {
+    if end-start <= 1 {
+        return fmt.Sprint(start)
+    }
+    return fmt.Sprintf("%d-%d", start, end-1)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "strings"
+
+    "box/textbox"
+)
+
+// mermaidEscaper replaces the characters Mermaid gives a meaning in note
+// text with entity codes.
+var mermaidEscaper = strings.NewReplacer("#", "#35;", ";", "#59;", "<", "#lt;", ">", "#gt;")
+
+// writeMermaidNote writes lines as a Mermaid sequence diagram note over
+// participant. The title leads the note in bold and the footer ends it in
+// italics; escape sequences and control characters are left out.
+func writeMermaidNote(w io.Writer, participant string, lines []string, opts textbox.Options) error {
+    var parts []string
+    if opts.Title != "" {
+        parts = append(parts, "<b>"+mermaidEscaper.Replace(plainText(opts.Title))+"</b>")
+    }
+    for _, line := range lines {
+        parts = append(parts, mermaidEscaper.Replace(plainText(line)))
+    }
+    if opts.Footer != "" {
+        parts = append(parts, "<i>"+mermaidEscaper.Replace(plainText(opts.Footer))+"</i>")
+    }
+    _, err := fmt.Fprintf(w, "Note over %s: %s\n", participant, strings.Join(parts, "<br/>"))
+    return err
+}
+
+// writePlantUMLNote writes lines as a floating PlantUML note. The title
+// leads the note in bold above a separator and the footer ends it.
+func writePlantUMLNote(w io.Writer, lines []string, opts textbox.Options) error {
+    var b strings.Builder
+    b.WriteString("note as N1\n")
+    if opts.Title != "" {
+        fmt.Fprintf(&b, "<b>%s</b>\n----\n", plainText(opts.Title))
+    }
+    for _, line := range lines {
+        // A line of its own reading "end note" would close the note early.
+        if strings.TrimSpace(line) == "end note" {
+            line = "~" + line
+        }
+        b.WriteString(plainText(line) + "\n")
+    }
+    if opts.Footer != "" {
+        fmt.Fprintf(&b, "----\n<i>%s</i>\n", plainText(opts.Footer))
+    }
+    b.WriteString("end note\n")
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// plainText removes escape sequences and control characters, which have no
+// place in diagram markup.
+func plainText(s string) string {
+    return textbox.Sanitize(s, textbox.Strict)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+    "encoding/base64"
+    "fmt"
+    "io"
+    "strings"
+
+    "box/textbox"
+)
+
+// writeOSC52 writes the OSC 52 escape sequence asking the terminal to set
+// the clipboard to text, without its escape sequences. Terminals that
+// support it honor it over SSH too; others ignore it.
+func writeOSC52(w io.Writer, text string) error {
+    lines := strings.Split(text, "\n")
+    for i, line := range lines {
+        lines[i] = textbox.Sanitize(line, textbox.Strict)
+    }
+    plain := strings.Join(lines, "\n")
+    _, err := fmt.Fprintf(w, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(plain)))
+    return err
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+    "errors"
+    "io"
+    "os"
+    "os/signal"
+    "syscall"
+)
+
+// ignoreBrokenPipe makes writes to a closed stdout fail with EPIPE instead of
+// killing the process, so that the error can be handled.
+func ignoreBrokenPipe() {
+    signal.Ignore(syscall.SIGPIPE)
+}
+
+// isBrokenPipe reports whether err comes from writing to a closed pipe.
+func isBrokenPipe(err error) bool {
+    return errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed)
+}
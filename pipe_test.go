@@ -0,0 +1,49 @@
+package main
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestClosedPipeIsBrokenPipe(t *testing.T) {
+    ignoreBrokenPipe()
+    in := filepath.Join(t.TempDir(), "in")
+    if err := os.WriteFile(in, []byte("hello\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    stdin, err := os.Open(in)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer stdin.Close()
+    r, w, err := os.Pipe()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer w.Close()
+    r.Close()
+
+    oldStdin, oldStdout := os.Stdin, os.Stdout
+    os.Stdin, os.Stdout = stdin, w
+    defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+
+    o, run := commandOptions("box", "box")
+    if err := o.Parse(nil); err != nil {
+        t.Fatal(err)
+    }
+    err = run(o.fs.Args())
+    if err == nil {
+        t.Fatal("writing to a closed pipe succeeded")
+    }
+    if !isBrokenPipe(err) {
+        t.Errorf("isBrokenPipe(%v) = false, want true so that box exits with %d", err, exitBrokenPipe)
+    }
+}
+
+func TestOtherErrorsAreNotBrokenPipe(t *testing.T) {
+    if isBrokenPipe(errors.New("disk full")) {
+        t.Error("isBrokenPipe reported a plain error as a broken pipe")
+    }
+}
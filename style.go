@@ -0,0 +1,182 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "box/textbox"
+)
+
+// Errors reported for an unusable style selection.
+var (
+    errCustomChar   = errors.New("Error: For -n 4, exactly one UTF-8 character must be provided with -f.")
+    errInvalidStyle = errors.New("Invalid style number or missing custom character. Please use -n 1-4 or provide a custom character with -f.")
+)
+
+// checkStyle validates the style selection before any input is read.
+func checkStyle(opts textbox.Options) error {
+    for option, color := range map[string]string{"--border-color": opts.BorderColor, "--title-color": opts.TitleColor} {
+        if _, err := textbox.ParseANSIColor(color); color != "" && err != nil {
+            return fmt.Errorf("%s: %v", option, err)
+        }
+    }
+    if opts.Theme != "" {
+        if _, ok := textbox.LookupTheme(opts.Theme); !ok {
+            return fmt.Errorf("unknown theme %q", opts.Theme)
+        }
+        return nil
+    }
+    if opts.Style == textbox.CustomStyle {
+        if _, err := textbox.CustomGlyph(opts.Char); errors.Is(err, textbox.ErrCustomChar) {
+            return errCustomChar
+        } else if err != nil {
+            return fmt.Errorf("-f: %v", err)
+        }
+        return nil
+    }
+    if _, ok := textbox.LookupStyle(opts.Style); ok {
+        return nil
+    }
+    if strings.Contains(opts.Style, ":") {
+        _, err := textbox.ParseBoxStyle(opts.Style)
+        var warning *textbox.StyleWarning
+        if errors.As(err, &warning) {
+            fmt.Fprintln(os.Stderr, "warning:", warning)
+            return nil
+        }
+        return err
+    }
+    return errInvalidStyle
+}
+
+// userStylesPath returns the file user defined styles are stored in.
+func userStylesPath() (string, error) {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "textbox", "styles"), nil
+}
+
+// registerUserStyles registers the styles of the user styles file. Each line
+// holds a name followed by the glyphs of the style as taken by
+// textbox.NewBoxStyle; blank lines and lines starting with # are ignored. A
+// missing file yields no styles.
+func registerUserStyles() error {
+    path, err := userStylesPath()
+    if err != nil {
+        return nil
+    }
+    f, err := os.Open(path)
+    if errors.Is(err, os.ErrNotExist) {
+        return nil
+    } else if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    scanner := bufio.NewScanner(f)
+    for n := 1; scanner.Scan(); n++ {
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+            continue
+        }
+        style, err := textbox.NewBoxStyle(fields[1:]...)
+        if err == nil {
+            err = textbox.RegisterStyle(fields[0], style)
+        }
+        if err != nil {
+            return fmt.Errorf("%s:%d: %v", path, n, err)
+        }
+    }
+    return scanner.Err()
+}
+
+// saveUserStyle appends a style to the user styles file.
+func saveUserStyle(name string, style textbox.BoxStyle) error {
+    path, err := userStylesPath()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+    if err != nil {
+        return err
+    }
+    if _, err := fmt.Fprintln(f, name+" "+strings.Join(style.Glyphs(), " ")); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+// registerStyleOptions declares the options selecting the frame style.
+func registerStyleOptions(o *optionSet, opts *textbox.Options) {
+    o.String(&opts.Style, "n", "style", opts.Style, "Style", "Box style: 1-4, a style name or \"TL:+ TR:+ BL:+ BR:+ H:- V:| TitleL:+ TitleR:+\"")
+    o.String(&opts.Char, "f", "char", opts.Char, "Style", "Custom UTF-8 character for style 4")
+    o.complete("style", "styles --names list", "1", "2", "3", "4")
+}
+
+// stylesCommand declares the options of the styles command.
+func stylesCommand(o *optionSet) func(args []string) error {
+    var names bool
+    o.synopsis = "list | show NAME | add NAME GLYPH..."
+    o.Bool(&names, "", "names", false, "Output", "List only the style names")
+
+    return func(args []string) error {
+        if len(args) == 0 {
+            o.fs.Usage()
+            return errors.New("styles: missing action")
+        }
+        return runStyles(args[0], args[1:], names)
+    }
+}
+
+// runStyles implements the styles actions: list, show and add.
+func runStyles(action string, args []string, namesOnly bool) error {
+    switch action {
+    case "list":
+        for _, name := range textbox.Styles() {
+            line := name
+            if !namesOnly {
+                style, _ := textbox.LookupStyle(name)
+                line = fmt.Sprintf("%-8s %s", name, strings.Join(style.Glyphs(), ""))
+            }
+            if _, err := fmt.Fprintln(os.Stdout, line); err != nil {
+                return err
+            }
+        }
+    case "show":
+        if len(args) != 1 {
+            return errors.New("styles show: expected a style name")
+        }
+        opts := textbox.DefaultOptions()
+        opts.Style, opts.Title = args[0], args[0]
+        if err := checkStyle(opts); err != nil {
+            return err
+        }
+        return textbox.NewBox([]string{"The quick brown fox", "jumps over the lazy dog."}, textbox.WithOptions(opts)).Render(os.Stdout)
+    case "add":
+        if len(args) < 2 {
+            return errors.New("styles add: expected a name and 1 or 8 glyphs")
+        }
+        name := args[0]
+        style, err := textbox.NewBoxStyle(args[1:]...)
+        if err == nil {
+            err = textbox.RegisterStyle(name, style)
+        }
+        if err != nil {
+            return fmt.Errorf("styles add: %v", err)
+        }
+        return saveUserStyle(name, style)
+    default:
+        return fmt.Errorf("styles: unknown action %q", action)
+    }
+    return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "maps"
+    "os"
+    "slices"
+    "strconv"
+    "strings"
+
+    "box/textbox"
+)
+
+// tableCommand declares the options of the table command which renders CSV or
+// TSV input as columns inside a box.
+func tableCommand(o *optionSet) func(args []string) error {
+    var tsv bool
+    opts := textbox.DefaultOptions()
+    registerStyleOptions(o, &opts)
+    o.String(&opts.Title, "t", "title", "", "Title", "Box title")
+    o.Bool(&tsv, "", "tsv", false, "Input", "Read tab separated instead of comma separated values")
+    o.Var((*columnAlign)(&opts.ColumnAlign), "", "col-align", "Layout", "Align the columns left, center or right, as in \"l,r,c\"; further columns are aligned left")
+    o.Bool(&opts.TableHeader, "", "header", false, "Layout", "Rule off the first row as column headers")
+    o.Int(&opts.Width, "w", "width", 0, "Layout", "Total width of the table; longer rows are cut")
+    o.Bool(&opts.CellWrap, "", "cell-wrap", false, "Layout", "Narrow the columns to fit --width or the terminal and wrap the cells within them")
+    o.Var(&columnMaxWidth{&opts.ColumnMaxWidth, &opts.ColumnMaxWidths}, "", "col-max-width", "Layout", "Wrap cells wider than N columns, in all columns or by column number as in \"3=40\"")
+    o.Bool(&opts.HeaderBold, "", "header-bold", false, "Style", "Draw the column headers of --header in bold")
+    o.String(&opts.HeaderColor, "", "header-color", "", "Style", "Column header color of --header, like --border-color")
+
+    return func(args []string) error {
+        if err := applyConfig(o, "", &opts); err != nil {
+            return err
+        }
+        return runTable(opts, tsv)
+    }
+}
+
+// columnAlign is the value of --col-align, a comma separated alignment per
+// column.
+type columnAlign []textbox.Alignment
+
+func (c *columnAlign) String() string {
+    names := make([]string, len(*c))
+    for i, a := range *c {
+        names[i] = a.String()
+    }
+    return strings.Join(names, ",")
+}
+
+func (c *columnAlign) Set(spec string) error {
+    aligns, err := textbox.ParseColumnAlign(spec)
+    if err == nil {
+        *c = aligns
+    }
+    return err
+}
+
+// columnMaxWidth is the value of --col-max-width, the width of all columns
+// or of numbered ones. Repeated flags add to each other.
+type columnMaxWidth struct {
+    all     *int
+    columns *map[int]int
+}
+
+func (c *columnMaxWidth) String() string {
+    if c.all == nil {
+        return ""
+    }
+    var items []string
+    if *c.all > 0 {
+        items = append(items, strconv.Itoa(*c.all))
+    }
+    cols := slices.Sorted(maps.Keys(*c.columns))
+    for _, col := range cols {
+        items = append(items, fmt.Sprintf("%d=%d", col, (*c.columns)[col]))
+    }
+    return strings.Join(items, ",")
+}
+
+func (c *columnMaxWidth) Set(spec string) error {
+    all, columns, err := textbox.ParseColumnMaxWidth(spec)
+    if err != nil {
+        return err
+    }
+    if all > 0 {
+        *c.all = all
+    }
+    for col, width := range columns {
+        if *c.columns == nil {
+            *c.columns = make(map[int]int)
+        }
+        (*c.columns)[col] = width
+    }
+    return nil
+}
+
+// runTable renders the table read from stdin.
+func runTable(opts textbox.Options, tsv bool) error {
+    if err := checkStyle(opts); err != nil {
+        return err
+    }
+    if !colorEnabled() {
+        opts.NoColor = true
+    }
+    if opts.CellWrap && opts.Width <= 0 {
+        if width, ok := terminalWidth(); ok {
+            opts.Width = width
+        }
+    }
+
+    r := csv.NewReader(os.Stdin)
+    if tsv {
+        r.Comma = '\t'
+        r.LazyQuotes = true
+    }
+    r.FieldsPerRecord = -1
+    var rows [][]string
+    for {
+        row, err := r.Read()
+        if err == io.EOF {
+            break
+        } else if err != nil {
+            return &readError{name: "stdin", lines: len(rows), err: err}
+        }
+        rows = append(rows, row)
+    }
+
+    return textbox.RenderTable(os.Stdout, rows, opts)
+}
@@ -0,0 +1,143 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Alignment places text within a wider space.
+type Alignment int
+
+// Alignments.
+const (
+    Left Alignment = iota
+    Center
+    Right
+    // Justify stretches the spaces between words to fill the width.
+    Justify
+)
+
+// alignmentNames are the names of the alignments, indexed by alignment.
+var alignmentNames = []string{"left", "center", "right", "justify"}
+
+func (a Alignment) String() string {
+    if a >= 0 && int(a) < len(alignmentNames) {
+        return alignmentNames[a]
+    }
+    return fmt.Sprintf("Alignment(%d)", int(a))
+}
+
+// ParseAlignment returns the alignment named left, center, right or
+// justify.
+func ParseAlignment(name string) (Alignment, error) {
+    for i, n := range alignmentNames {
+        if strings.EqualFold(name, n) {
+            return Alignment(i), nil
+        }
+    }
+    return Left, fmt.Errorf("invalid alignment %q, use left, center, right or justify", name)
+}
+
+// columnAlignNames are the abbreviations of the column alignments.
+var columnAlignNames = map[string]Alignment{"l": Left, "c": Center, "r": Right}
+
+// ParseColumnAlign parses a comma separated list of alignments, one per
+// table column, each left, center or right or abbreviated l, c or r.
+func ParseColumnAlign(spec string) ([]Alignment, error) {
+    var aligns []Alignment
+    for _, name := range strings.Split(spec, ",") {
+        name = strings.ToLower(strings.TrimSpace(name))
+        a, ok := columnAlignNames[name]
+        if parsed, err := ParseAlignment(name); err == nil && parsed != Justify {
+            a, ok = parsed, true
+        }
+        if !ok {
+            return nil, fmt.Errorf("invalid column alignment %q, use left, center or right (l, c, r)", name)
+        }
+        aligns = append(aligns, a)
+    }
+    return aligns, nil
+}
+
+// MarshalText encodes the alignment as its name.
+func (a Alignment) MarshalText() ([]byte, error) {
+    if a < 0 || int(a) >= len(alignmentNames) {
+        return nil, fmt.Errorf("invalid alignment %d", int(a))
+    }
+    return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an alignment name.
+func (a *Alignment) UnmarshalText(text []byte) error {
+    parsed, err := ParseAlignment(string(text))
+    if err == nil {
+        *a = parsed
+    }
+    return err
+}
+
+// Set implements flag.Value.
+func (a *Alignment) Set(name string) error {
+    return a.UnmarshalText([]byte(name))
+}
+
+// VisualPad pads s with spaces to width columns according to a. Justify
+// widens the spaces between the words of s instead; s without spaces is
+// aligned left. Strings at least width columns wide are returned unchanged.
+func VisualPad(s string, width int, a Alignment) string {
+    pad := width - visualLength(s)
+    if pad <= 0 {
+        return s
+    }
+    switch a {
+    case Center:
+        return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+    case Right:
+        return strings.Repeat(" ", pad) + s
+    case Justify:
+        if j, ok := justify(s, pad); ok {
+            return j
+        }
+    }
+    return s + strings.Repeat(" ", pad)
+}
+
+// AlignLines pads every line to width columns according to a. With Justify
+// the last line is aligned left, like the last line of a paragraph.
+func AlignLines(lines []string, width int, a Alignment) []string {
+    aligned := make([]string, len(lines))
+    for i, line := range lines {
+        if a == Justify && i == len(lines)-1 {
+            aligned[i] = VisualPad(line, width, Left)
+            continue
+        }
+        aligned[i] = VisualPad(line, width, a)
+    }
+    return aligned
+}
+
+// justify distributes pad extra spaces over the gaps between the words of
+// s, the leftmost gaps taking the remainder. It fails for a single word.
+func justify(s string, pad int) (string, bool) {
+    indent := s[:len(s)-len(strings.TrimLeft(s, " "))]
+    words := strings.Fields(s)
+    gaps := len(words) - 1
+    if gaps < 1 {
+        return s, false
+    }
+    // Runs of spaces collapse to one, returning their extra columns.
+    pad += visualLength(s) - visualLength(indent+strings.Join(words, " "))
+    var b strings.Builder
+    b.WriteString(indent)
+    for i, word := range words {
+        b.WriteString(word)
+        if i < gaps {
+            n := 1 + pad/gaps
+            if i < pad%gaps {
+                n++
+            }
+            b.WriteString(strings.Repeat(" ", n))
+        }
+    }
+    return b.String(), true
+}
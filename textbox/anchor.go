@@ -0,0 +1,52 @@
+package textbox
+
+import "fmt"
+
+// Edge is the side of a box another box is anchored to.
+type Edge int
+
+// Edges. Their names are set apart from the alignments Left and Right.
+const (
+    EdgeTop Edge = iota
+    EdgeBottom
+    EdgeLeft
+    EdgeRight
+)
+
+func (e Edge) String() string {
+    switch e {
+    case EdgeTop:
+        return "top"
+    case EdgeBottom:
+        return "bottom"
+    case EdgeLeft:
+        return "left"
+    case EdgeRight:
+        return "right"
+    }
+    return fmt.Sprintf("Edge(%d)", int(e))
+}
+
+// Anchor returns a box drawing b offset cells away from the edge of ref,
+// lined up with the top of ref beside it or its left side above or below
+// it. Both boxes keep their size; spaces fill the rest of the rectangle
+// around them.
+func Anchor(b, ref *Box, edge Edge, offset int) (*Box, error) {
+    if offset < 0 {
+        return nil, fmt.Errorf("negative anchor offset %d", offset)
+    }
+    // Padded boxes are placed in the grid as they are, without being
+    // stretched to the size of their row and column.
+    refCell := Pad(ref, 0, 0, 0, 0)
+    switch edge {
+    case EdgeTop:
+        return Grid(2, 1, []*Box{Pad(b, 0, 0, offset, 0), refCell})
+    case EdgeBottom:
+        return Grid(2, 1, []*Box{refCell, Pad(b, offset, 0, 0, 0)})
+    case EdgeLeft:
+        return Grid(1, 2, []*Box{Pad(b, 0, offset, 0, 0), refCell})
+    case EdgeRight:
+        return Grid(1, 2, []*Box{refCell, Pad(b, 0, 0, 0, offset)})
+    }
+    return nil, fmt.Errorf("invalid edge %v", edge)
+}
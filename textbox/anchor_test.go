@@ -0,0 +1,40 @@
+package textbox
+
+import (
+    "bytes"
+    "testing"
+)
+
+func TestAnchor(t *testing.T) {
+    ref := NewBox([]string{"ref", "two"})
+    b := NewBox([]string{"b"})
+    tests := []struct {
+        edge Edge
+        want string
+    }{
+        {EdgeRight, "┌─────┐ ┌───┐\n│ ref │ │ b │\n│ two │ └───┘\n└─────┘      \n"},
+        {EdgeLeft, "┌───┐ ┌─────┐\n│ b │ │ ref │\n└───┘ │ two │\n      └─────┘\n"},
+        {EdgeBottom, "┌─────┐\n│ ref │\n│ two │\n└─────┘\n       \n┌───┐  \n│ b │  \n└───┘  \n"},
+        {EdgeTop, "┌───┐  \n│ b │  \n└───┘  \n       \n┌─────┐\n│ ref │\n│ two │\n└─────┘\n"},
+    }
+    for _, tt := range tests {
+        a, err := Anchor(b, ref, tt.edge, 1)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var buf bytes.Buffer
+        if err := a.Render(&buf); err != nil {
+            t.Fatal(err)
+        }
+        if buf.String() != tt.want {
+            t.Errorf("anchored at the %v edge:\n%s\nwant:\n%s", tt.edge, buf.String(), tt.want)
+        }
+    }
+
+    if _, err := Anchor(b, ref, EdgeRight, -1); err == nil {
+        t.Error("negative offset succeeded, want an error")
+    }
+    if _, err := Anchor(b, ref, Edge(9), 0); err == nil {
+        t.Error("invalid edge succeeded, want an error")
+    }
+}
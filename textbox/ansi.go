@@ -0,0 +1,134 @@
+package textbox
+
+import (
+    "regexp"
+    "strings"
+    "sync/atomic"
+    "unicode/utf8"
+
+    "github.com/mattn/go-runewidth"
+    "github.com/rivo/uniseg"
+)
+
+// ansiPattern matches CSI sequences such as colors and OSC sequences such as
+// hyperlinks.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Text attributes.
+const (
+    sgrUnderline   = "\x1b[4m"
+    sgrNoUnderline = "\x1b[24m"
+    sgrBold        = "\x1b[1m"
+    sgrNoBold      = "\x1b[22m"
+)
+
+// stripANSI removes the escape sequences from s.
+func stripANSI(s string) string {
+    if !strings.Contains(s, "\x1b") {
+        return s
+    }
+    return ansiPattern.ReplaceAllString(s, "")
+}
+
+// cell is one grapheme cluster or one escape sequence of a line with its
+// width in columns. Escape sequences have no width.
+type cell struct {
+    s     string
+    width int
+}
+
+// cells splits s into grapheme clusters and escape sequences.
+func cells(s string) []cell {
+    var cs []cell
+    start := 0
+    for _, loc := range ansiPattern.FindAllStringIndex(s, -1) {
+        cs = appendClusters(cs, s[start:loc[0]])
+        cs = append(cs, cell{s: s[loc[0]:loc[1]]})
+        start = loc[1]
+    }
+    return appendClusters(cs, s[start:])
+}
+
+// appendClusters appends one cell per grapheme cluster of s.
+func appendClusters(cs []cell, s string) []cell {
+    state := -1
+    for s != "" {
+        var cluster string
+        var width int
+        cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+        cs = append(cs, cell{s: cluster, width: clusterWidth(cluster, width)})
+    }
+    return cs
+}
+
+// ambiguousWide is set when East Asian ambiguous characters are two columns
+// wide.
+var ambiguousWide atomic.Bool
+
+// SetAmbiguousWide sets whether East Asian ambiguous characters such as ±
+// and · are measured two columns wide, as CJK terminals show them. Like the
+// width tables it adjusts, the setting applies to the whole process.
+func SetAmbiguousWide(wide bool) {
+    ambiguousWide.Store(wide)
+}
+
+// AmbiguousWide reports whether East Asian ambiguous characters are measured
+// two columns wide.
+func AmbiguousWide() bool {
+    return ambiguousWide.Load()
+}
+
+// clusterWidth corrects the width uniseg reports for a grapheme cluster:
+// keycap sequences such as 1️⃣ are shown as emoji two columns wide, and so
+// are ambiguous characters if requested. Soft hyphens are invisible.
+func clusterWidth(cluster string, width int) int {
+    if strings.HasSuffix(cluster, "\u20e3") {
+        return 2
+    }
+    if cluster == softHyphen {
+        return 0
+    }
+    if width == 1 && ambiguousWide.Load() {
+        if r, _ := utf8.DecodeRuneInString(cluster); runewidth.IsAmbiguousWidth(r) {
+            return 2
+        }
+    }
+    return width
+}
+
+// stringWidth returns the width of s, which holds no escape sequences.
+func stringWidth(s string) int {
+    w, state := 0, -1
+    for s != "" {
+        var cluster string
+        var width int
+        cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+        w += clusterWidth(cluster, width)
+    }
+    return w
+}
+
+// truncate cuts s to at most width columns, keeping its escape sequences.
+func truncate(s string, width int) string {
+    var b strings.Builder
+    w, full := 0, false
+    for _, c := range cells(s) {
+        if c.width > 0 && (full || w+c.width > width) {
+            full = true
+            continue
+        }
+        w += c.width
+        b.WriteString(c.s)
+    }
+    return b.String()
+}
+
+// isSGR reports whether c sets colors or text attributes.
+func (c cell) isSGR() bool {
+    return c.width == 0 && strings.HasPrefix(c.s, "\x1b[") && strings.HasSuffix(c.s, "m")
+}
+
+// isReset reports whether c resets all text attributes.
+func (c cell) isReset() bool {
+    return c.s == "\x1b[m" || c.s == "\x1b[0m"
+}
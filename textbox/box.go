@@ -0,0 +1,138 @@
+package textbox
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "regexp"
+    "strings"
+    "text/template"
+)
+
+// Box is a box with its content and options.
+type Box struct {
+    lines []string
+    opts  Options
+    // cells make the box a grid of boxes, drawn in place of a frame
+    // around lines.
+    cells [][]*Box
+    // margin makes the box another box with blank space around it.
+    margin *margin
+}
+
+// framed reports whether b frames its lines, rather than being composed
+// of other boxes.
+func (b *Box) framed() bool {
+    return b.cells == nil && b.margin == nil
+}
+
+// NewBox returns a box framing lines with the default options changed by
+// opts.
+func NewBox(lines []string, opts ...Option) *Box {
+    return &Box{lines: append([]string(nil), lines...), opts: NewOptions(opts...)}
+}
+
+// Render draws the box to w. It returns the first error met while laying
+// out the box or writing it.
+func (b *Box) Render(w io.Writer) error {
+    if b.framed() {
+        return Render(w, b.lines, b.opts)
+    }
+    rows, err := b.rows(b.opts)
+    if err != nil {
+        return err
+    }
+    for _, row := range rows {
+        if _, err := fmt.Fprintln(w, row); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// NewBoxFromTemplate returns a box with opts framing the lines of the
+// text/template tmpl executed with data. A final newline ends the last line
+// rather than adding an empty one.
+func NewBoxFromTemplate(tmpl string, data any, opts Options) (*Box, error) {
+    t, err := template.New("box").Parse(tmpl)
+    if err != nil {
+        return nil, fmt.Errorf("parsing template: %w", err)
+    }
+    var buf strings.Builder
+    if err := t.Execute(&buf, data); err != nil {
+        return nil, fmt.Errorf("executing template: %w", err)
+    }
+    var lines []string
+    if buf.Len() > 0 {
+        lines = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    }
+    return NewBox(lines, WithOptions(opts)), nil
+}
+
+// MarshalText returns the box as Render draws it.
+func (b *Box) MarshalText() ([]byte, error) {
+    var buf bytes.Buffer
+    if err := b.Render(&buf); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// UnmarshalText replaces b with the box drawn in text, as read by ParseBox.
+func (b *Box) UnmarshalText(text []byte) error {
+    parsed, err := ParseBox(string(text))
+    if err != nil {
+        return err
+    }
+    *b = *parsed
+    return nil
+}
+
+// Title returns the title of the box, empty if it has none.
+func (b *Box) Title() string {
+    return b.opts.Title
+}
+
+// SetTitle replaces the title of the box. The box is sized for it when it
+// is drawn, so a wider title widens the box.
+func (b *Box) SetTitle(t string) {
+    b.opts.Title = t
+}
+
+// ClearTitle removes the title of the box.
+func (b *Box) ClearTitle() {
+    b.opts.Title = ""
+}
+
+// SetStyle draws the box with s from now on, in place of the style or
+// theme it was created with. It fails, leaving the box unchanged, if a
+// component of s is empty or zero columns wide.
+func (b *Box) SetStyle(s BoxStyle) error {
+    if err := checkGlyphs(s.Glyphs()); err != nil {
+        return err
+    }
+    b.opts.Style, b.opts.Theme = s.String(), ""
+    return nil
+}
+
+// AddHighlightRule colors the content lines matching the regular expression
+// pattern in color. Rules are tried in the order they were added, before
+// those of the theme; the first match wins.
+func (b *Box) AddHighlightRule(pattern string, color ANSIColor) error {
+    re, err := regexp.Compile(pattern)
+    if err != nil {
+        return err
+    }
+    b.opts.HighlightRules = append(b.opts.HighlightRules, HighlightRule{Pattern: re, Color: color})
+    return nil
+}
+
+// Lines returns a copy of the content lines of the box, without the border.
+func (b *Box) Lines() []string {
+    return append([]string(nil), b.lines...)
+}
+
+// SetLines replaces the content lines of the box with a copy of lines.
+func (b *Box) SetLines(lines []string) {
+    b.lines = append([]string(nil), lines...)
+}
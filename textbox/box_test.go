@@ -0,0 +1,277 @@
+package textbox
+
+import (
+    "bytes"
+    "errors"
+    "regexp"
+    "strings"
+    "testing"
+)
+
+// renderRows renders b and returns its content rows.
+func renderRows(t *testing.T, b *Box) []string {
+    t.Helper()
+    var buf bytes.Buffer
+    if err := b.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    return rows[1 : len(rows)-1]
+}
+
+func TestHighlightRuleFirstMatchWins(t *testing.T) {
+    b := NewBox([]string{"ERROR disk full", "WARNING low memory", "info ok"})
+    for _, r := range []struct {
+        pattern string
+        color   ANSIColor
+    }{
+        {"ERR", Red},
+        {"ERROR", Yellow},
+        {"(ERROR|WARNING)", Magenta},
+    } {
+        if err := b.AddHighlightRule(r.pattern, r.color); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    rows := renderRows(t, b)
+    want := []string{
+        Colorize("ERROR disk full", Red),
+        Colorize("WARNING low memory", Magenta),
+        "info ok",
+    }
+    for i, row := range rows {
+        if !strings.Contains(row, want[i]) {
+            t.Errorf("row %d = %q, want it to contain %q", i, row, want[i])
+        }
+    }
+    if strings.Contains(rows[2], "\x1b[") {
+        t.Errorf("unmatched row %q is colored", rows[2])
+    }
+}
+
+func TestHighlightRulePriorityOverTheme(t *testing.T) {
+    theme := Theme{
+        BoxStyle:     builtinStyles[0].style,
+        ContentColor: Green,
+        HighlightRules: []HighlightRule{
+            {Pattern: regexp.MustCompile("fail"), Color: Blue},
+            {Pattern: regexp.MustCompile("pass"), Color: Cyan},
+        },
+    }
+    if err := RegisterTheme("highlight-test", theme); err != nil {
+        t.Fatal(err)
+    }
+    b := NewBox([]string{"test fail", "test pass", "skipped"}, func(o *Options) { o.Theme = "highlight-test" })
+    if err := b.AddHighlightRule("fail", RGB{255, 0, 0}); err != nil {
+        t.Fatal(err)
+    }
+
+    rows := renderRows(t, b)
+    want := []string{
+        Colorize("test fail", RGB{255, 0, 0}),
+        Colorize("test pass", Cyan),
+        Colorize("skipped", Green),
+    }
+    for i, row := range rows {
+        if !strings.Contains(row, want[i]) {
+            t.Errorf("row %d = %q, want it to contain %q", i, row, want[i])
+        }
+    }
+}
+
+func TestAddHighlightRuleInvalidPattern(t *testing.T) {
+    b := NewBox(nil)
+    if err := b.AddHighlightRule("(", Red); err == nil {
+        t.Error("AddHighlightRule accepted an invalid pattern")
+    }
+    if len(b.opts.HighlightRules) != 0 {
+        t.Errorf("invalid rule was added: %v", b.opts.HighlightRules)
+    }
+}
+
+// failWriter fails every write.
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestBoxRender(t *testing.T) {
+    b := NewBox([]string{"hello"})
+    var want bytes.Buffer
+    if err := Render(&want, []string{"hello"}, DefaultOptions()); err != nil {
+        t.Fatal(err)
+    }
+    var got bytes.Buffer
+    if err := b.Render(&got); err != nil {
+        t.Fatal(err)
+    }
+    if got.String() != want.String() {
+        t.Errorf("Render = %q, want %q", got.String(), want.String())
+    }
+    if err := b.Render(failWriter{}); err == nil {
+        t.Error("Render to a failing writer succeeded, want an error")
+    }
+}
+
+func TestSetStyle(t *testing.T) {
+    b := NewBox([]string{"x"}, WithStyle("1"))
+    ascii := BoxStyle{
+        TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+        Horizontal: "-", Vertical: "|", TitleLeft: "+", TitleRight: "+",
+    }
+    if err := b.SetStyle(ascii); err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := b.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    if want := "+---+\n| x |\n+---+\n"; buf.String() != want {
+        t.Errorf("Render after SetStyle = %q, want %q", buf.String(), want)
+    }
+
+    bad := ascii
+    bad.Horizontal = ""
+    if err := b.SetStyle(bad); err == nil {
+        t.Error("SetStyle with an empty glyph succeeded, want an error")
+    }
+    if b.opts.Style != ascii.String() {
+        t.Errorf("failed SetStyle changed the style to %q", b.opts.Style)
+    }
+}
+
+func TestSetTitle(t *testing.T) {
+    b := NewBox([]string{"x"})
+    if got := b.Title(); got != "" {
+        t.Errorf("Title of a new box = %q, want none", got)
+    }
+    b.SetTitle("a wide title")
+    if got := b.Title(); got != "a wide title" {
+        t.Errorf("Title = %q after SetTitle", got)
+    }
+    var buf bytes.Buffer
+    if err := b.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    if want := "┌─┘ a wide title └─┐\n"; !strings.HasPrefix(buf.String(), want) {
+        t.Errorf("Render after SetTitle = %q, want it to start with %q", buf.String(), want)
+    }
+    b.ClearTitle()
+    buf.Reset()
+    if err := b.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    if want := "┌───┐\n│ x │\n└───┘\n"; buf.String() != want {
+        t.Errorf("Render after ClearTitle = %q, want %q", buf.String(), want)
+    }
+}
+
+func TestNewBoxFromTemplate(t *testing.T) {
+    data := struct {
+        Name  string
+        Items []string
+    }{"disk", []string{"sda", "sdb"}}
+    for _, tc := range []struct {
+        tmpl string
+        want []string
+    }{
+        {"{{.Name}}\n{{range .Items}}- {{.}}\n{{end}}", []string{"disk", "- sda", "- sdb"}},
+        {"{{.Name}}", []string{"disk"}},
+        {"", nil},
+        {"\n", []string{""}},
+    } {
+        b, err := NewBoxFromTemplate(tc.tmpl, data, DefaultOptions())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := b.Lines(); strings.Join(got, "|") != strings.Join(tc.want, "|") || len(got) != len(tc.want) {
+            t.Errorf("NewBoxFromTemplate(%q) lines = %q, want %q", tc.tmpl, got, tc.want)
+        }
+    }
+
+    if _, err := NewBoxFromTemplate("{{.Name", data, DefaultOptions()); err == nil || !strings.Contains(err.Error(), "parsing template") {
+        t.Errorf("bad template: error %v, want one about parsing", err)
+    }
+    if _, err := NewBoxFromTemplate("{{.Missing}}", data, DefaultOptions()); err == nil || !strings.Contains(err.Error(), "executing template") {
+        t.Errorf("missing field: error %v, want one about executing", err)
+    }
+}
+
+func TestJoinHorizontal(t *testing.T) {
+    shadowed := NewBox([]string{"s"}, func(o *Options) { o.Shadow = DropShadow })
+    tab := NewBox([]string{"content"}, WithTitle("Tab"), WithWidth(14), func(o *Options) { o.TitleTab = true })
+    tests := []struct {
+        name  string
+        boxes []*Box
+        want  string
+    }{
+        // The shadow row below a box drawn taller is kept.
+        {"shadow", []*Box{shadowed, NewBox([]string{"a", "b", "c"})},
+            "┌───┐  ┌───┐\n│ s │░ │ a │\n│   │░ │ b │\n│   │░ │ c │\n└───┘░ └───┘\n ░░░░░      \n"},
+        // The narrow rows of the tab do not shift the box beside it.
+        {"title tab", []*Box{tab, NewBox([]string{"x"})},
+            "   ┌─────┐     ┌───┐\n   │ Tab │     │ x │\n┌──┴─────┴───┐ │   │\n│ content    │ │   │\n└────────────┘ └───┘\n"},
+    }
+    for _, tt := range tests {
+        var buf bytes.Buffer
+        if err := Join(&buf, Horizontal, 1, tt.boxes...); err != nil {
+            t.Fatal(err)
+        }
+        if buf.String() != tt.want {
+            t.Errorf("%s: joined boxes =\n%s\nwant\n%s", tt.name, buf.String(), tt.want)
+        }
+    }
+}
+
+func TestSplitAt(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Title, opts.Footer = "T", "F"
+    b := NewBox([]string{"a", "b", "c"}, WithOptions(opts))
+    top, bottom, err := SplitAt(b, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := Join(&buf, Vertical, 0, top, bottom); err != nil {
+        t.Fatal(err)
+    }
+    want := "┌─┘ T └─┐\n│ a     │\n└───────┘\n┌─────┐\n│ b   │\n│ c   │\n└─ F ─┘\n"
+    if buf.String() != want {
+        t.Errorf("split boxes =\n%s\nwant\n%s", buf.String(), want)
+    }
+    if got := b.Lines(); len(got) != 3 || b.Title() != "T" {
+        t.Errorf("SplitAt changed the box to %q titled %q", got, b.Title())
+    }
+
+    for _, row := range []int{0, 3, -1} {
+        if _, _, err := SplitAt(b, row); err == nil {
+            t.Errorf("SplitAt(b, %d) of 3 lines succeeded, want an error", row)
+        }
+    }
+}
+
+func TestColumnSplit(t *testing.T) {
+    b := NewBox([]string{"ab中d", "x", "\x1b[31mredred\x1b[0m"})
+    left, right, err := ColumnSplit(b, 3)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := Join(&buf, Horizontal, 0, left, right); err != nil {
+        t.Fatal(err)
+    }
+    want := "┌─────┐┌─────┐\n" +
+        "│ ab  ││ 中d │\n" +
+        "│ x   ││     │\n" +
+        "│ \x1b[31mred\x1b[0m ││ \x1b[31mred\x1b[0m │\n" +
+        "└─────┘└─────┘\n"
+    if buf.String() != want {
+        t.Errorf("split boxes = %q, want %q", buf.String(), want)
+    }
+
+    for _, col := range []int{0, 6, -1} {
+        if _, _, err := ColumnSplit(b, col); err == nil {
+            t.Errorf("ColumnSplit(b, %d) of 6 columns succeeded, want an error", col)
+        }
+    }
+}
@@ -0,0 +1,169 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Canvas is a block of width columns and height rows onto which boxes are
+// drawn at any position, each over what is there already. Unlike a Grid or
+// Anchor, the boxes on a canvas are not laid out against each other: they
+// may overlap, and the last one placed shows.
+type Canvas struct {
+    width, height int
+    grid          [][]canvasCell
+}
+
+// canvasCell is one column of a canvas: a grapheme cluster with the SGR
+// sequences coloring it. The columns after a wide cluster are left empty.
+type canvasCell struct {
+    s     string
+    style string
+}
+
+// blankCell is an uncolored space.
+var blankCell = canvasCell{s: " "}
+
+// NewCanvas returns a canvas of width columns and height rows filled with
+// spaces. Negative sizes count as 0.
+func NewCanvas(width, height int) *Canvas {
+    c := &Canvas{width: max(width, 0), height: max(height, 0)}
+    c.grid = make([][]canvasCell, c.height)
+    for i := range c.grid {
+        c.grid[i] = make([]canvasCell, c.width)
+        for j := range c.grid[i] {
+            c.grid[i][j] = blankCell
+        }
+    }
+    return c
+}
+
+// Width returns the number of columns of c.
+func (c *Canvas) Width() int { return c.width }
+
+// Height returns the number of rows of c.
+func (c *Canvas) Height() int { return c.height }
+
+// Resize changes the size of c to width columns and height rows, keeping
+// what is on it in the top left corner. New rows and columns are filled
+// with spaces. c can shrink only by rows and columns that are blank;
+// Resize fails, leaving c as it is, if anything drawn on c would be cut
+// off.
+func (c *Canvas) Resize(width, height int) error {
+    width, height = max(width, 0), max(height, 0)
+    for i, row := range c.grid {
+        for j, cell := range row {
+            if (i >= height || j >= width) && cell != blankCell {
+                return fmt.Errorf("resizing a canvas of %d×%d to %d×%d cuts off row %d, column %d", c.width, c.height, width, height, i, j)
+            }
+        }
+    }
+    grid := NewCanvas(width, height).grid
+    for i := range min(height, c.height) {
+        copy(grid[i], c.grid[i][:min(width, c.width)])
+    }
+    c.width, c.height, c.grid = width, height, grid
+    return nil
+}
+
+// Place draws b with its top left corner at row and col of c, which count
+// from 0 and may be negative. What falls outside the canvas is cut off,
+// and so is a wide character across its edge. Columns beyond the end of a
+// row of b, as beside a drop shadow, leave the canvas as it is.
+func (c *Canvas) Place(b *Box, row, col int) error {
+    rows, err := b.rows(b.opts)
+    if err != nil {
+        return err
+    }
+    for i, line := range rows {
+        if r := row + i; r >= 0 && r < c.height {
+            placeLine(c.grid[r], line, col)
+        }
+    }
+    return nil
+}
+
+// placeLine draws line into the cells of a canvas row from column col on.
+func placeLine(row []canvasCell, line string, col int) {
+    type placed struct {
+        at, width int
+        cell      canvasCell
+    }
+    var clusters []placed
+    var active []string
+    at := col
+    for _, cs := range cells(line) {
+        switch {
+        case cs.isReset():
+            active = nil
+        case cs.isSGR():
+            active = append(active, cs.s)
+        case cs.width > 0:
+            clusters = append(clusters, placed{at, cs.width, canvasCell{s: cs.s, style: strings.Join(active, "")}})
+            at += cs.width
+        }
+        // Other escape sequences and zero width clusters take no column.
+    }
+    // What is drawn over goes first, so that no half of a wide cluster
+    // remains.
+    for k := max(col, 0); k < min(at, len(row)); k++ {
+        clearWide(row, k)
+    }
+    for _, p := range clusters {
+        if p.at < 0 || p.at+p.width > len(row) {
+            // Only part of a wide cluster is on the canvas.
+            for k := max(p.at, 0); k < min(p.at+p.width, len(row)); k++ {
+                row[k] = blankCell
+            }
+            continue
+        }
+        row[p.at] = p.cell
+        for k := p.at + 1; k < p.at+p.width; k++ {
+            row[k] = canvasCell{}
+        }
+    }
+}
+
+// clearWide blanks the wide cluster that column k of row is part of.
+func clearWide(row []canvasCell, k int) {
+    start := k
+    for start > 0 && row[start].s == "" {
+        start--
+    }
+    end := start + 1
+    for end < len(row) && row[end].s == "" {
+        end++
+    }
+    if end-start > 1 {
+        for i := start; i < end; i++ {
+            row[i] = blankCell
+        }
+    }
+}
+
+// String returns the rows of c, each ended by a newline, with the colors of
+// the boxes placed on it.
+func (c *Canvas) String() string {
+    var b strings.Builder
+    for _, row := range c.grid {
+        style := ""
+        for _, cell := range row {
+            if cell.s == "" {
+                continue
+            }
+            if cell.style != style {
+                if style != "" {
+                    b.WriteString("\x1b[0m")
+                }
+                b.WriteString(cell.style)
+                style = cell.style
+            }
+            b.WriteString(cell.s)
+        }
+        if style != "" {
+            b.WriteString("\x1b[0m")
+        }
+        b.WriteByte('\n')
+    }
+    return b.String()
+}
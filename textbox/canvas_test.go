@@ -0,0 +1,67 @@
+package textbox
+
+import "testing"
+
+func TestCanvasPlace(t *testing.T) {
+    c := NewCanvas(12, 5)
+    if err := c.Place(NewBox([]string{"one"}), 0, 0); err != nil {
+        t.Fatal(err)
+    }
+    if err := c.Place(NewBox([]string{"two"}), 2, 5); err != nil {
+        t.Fatal(err)
+    }
+    // Boxes overlap, the later on top; what falls off the canvas is cut.
+    if err := c.Place(NewBox([]string{"x"}), 3, 9); err != nil {
+        t.Fatal(err)
+    }
+    want := "┌─────┐     \n│ one │     \n└────┌─────┐\n     │ tw┌──\n     └───│ x\n"
+    if got := c.String(); got != want {
+        t.Errorf("canvas:\n%s\nwant:\n%s", got, want)
+    }
+}
+
+func TestCanvasWide(t *testing.T) {
+    c := NewCanvas(6, 1)
+    c.grid[0] = []canvasCell{{s: "東"}, {}, {s: "京"}, {}, {s: "x"}, {s: "y"}}
+    // Covering half of a wide character blanks the other half.
+    placeLine(c.grid[0], "a", 1)
+    if got, want := c.String(), " a京xy\n"; got != want {
+        t.Errorf("canvas %q, want %q", got, want)
+    }
+    // A wide character across the edge is cut off.
+    placeLine(c.grid[0], "\x1b[31m東京\x1b[0m", 3)
+    if got, want := c.String(), " a \x1b[31m東\x1b[0m \n"; got != want {
+        t.Errorf("canvas %q, want %q", got, want)
+    }
+}
+
+func TestCanvasResize(t *testing.T) {
+    c := NewCanvas(2, 1)
+    placeLine(c.grid[0], "ab", 0)
+    if err := c.Resize(4, 2); err != nil {
+        t.Fatal(err)
+    }
+    if err := c.Place(NewBox([]string{"x"}), 2, 3); err != nil {
+        t.Fatal(err)
+    }
+    if got, want := c.String(), "ab  \n    \n"; got != want {
+        t.Errorf("grown canvas %q, want %q", got, want)
+    }
+    if c.Width() != 4 || c.Height() != 2 {
+        t.Errorf("canvas of %d×%d, want 4×2", c.Width(), c.Height())
+    }
+
+    // Blank rows and columns may go, anything else may not.
+    if err := c.Resize(2, 1); err != nil {
+        t.Fatal(err)
+    }
+    if got, want := c.String(), "ab\n"; got != want {
+        t.Errorf("shrunk canvas %q, want %q", got, want)
+    }
+    if err := c.Resize(1, 1); err == nil {
+        t.Error("resizing over content succeeded, want an error")
+    }
+    if got, want := c.String(), "ab\n"; got != want {
+        t.Errorf("canvas after a failed resize %q, want %q", got, want)
+    }
+}
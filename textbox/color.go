@@ -0,0 +1,163 @@
+package textbox
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// ANSIColor is a terminal foreground color: a Basic color, a Color256 index
+// or an RGB triplet.
+type ANSIColor interface {
+    // params returns the SGR parameters selecting the color.
+    params() string
+}
+
+// Basic is one of the 16 colors of 4-bit terminals.
+type Basic uint8
+
+// The basic colors.
+const (
+    Black Basic = iota
+    Red
+    Green
+    Yellow
+    Blue
+    Magenta
+    Cyan
+    White
+    BrightBlack
+    BrightRed
+    BrightGreen
+    BrightYellow
+    BrightBlue
+    BrightMagenta
+    BrightCyan
+    BrightWhite
+)
+
+func (c Basic) params() string {
+    if c >= BrightBlack {
+        return fmt.Sprint(90 + int(c-BrightBlack)%8)
+    }
+    return fmt.Sprint(30 + int(c))
+}
+
+// Color256 is an index into the 256 color palette.
+type Color256 uint8
+
+func (c Color256) params() string {
+    return fmt.Sprintf("38;5;%d", c)
+}
+
+// RGB is a 24-bit color.
+type RGB struct {
+    R, G, B uint8
+}
+
+func (c RGB) params() string {
+    return fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B)
+}
+
+// Colorize returns s in color, followed by a reset of all attributes. A nil
+// color returns s unchanged.
+func Colorize(s string, color ANSIColor) string {
+    if color == nil {
+        return s
+    }
+    return "\x1b[" + color.params() + "m" + s + "\x1b[0m"
+}
+
+// colorNames are the names of the basic colors, indexed by color.
+var colorNames = []string{
+    "black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+    "brightblack", "brightred", "brightgreen", "brightyellow",
+    "brightblue", "brightmagenta", "brightcyan", "brightwhite",
+}
+
+// rgbPattern matches CSS-like rgb(r,g,b) colors.
+var rgbPattern = regexp.MustCompile(`^rgb\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*\)$`)
+
+// sgr256Pattern matches the SGR parameters of a 256 color index.
+var sgr256Pattern = regexp.MustCompile(`^38;5;(\d+)$`)
+
+// ParseANSIColor parses a color given as a basic SGR number (31, 91), a 256
+// color index (196 or 38;5;196), hex RGB (#ff0000 or ff0000, also 112233),
+// rgb(255,0,0) or the name of a basic color (red, bright-red).
+func ParseANSIColor(s string) (ANSIColor, error) {
+    s = strings.ToLower(strings.TrimSpace(s))
+    if m := sgr256Pattern.FindStringSubmatch(s); m != nil {
+        if n, err := strconv.Atoi(m[1]); err == nil && n <= 255 {
+            return Color256(n), nil
+        }
+        return nil, fmt.Errorf("color %q: number out of range", s)
+    }
+    name := strings.NewReplacer("-", "", "_", "", " ", "").Replace(s)
+    for i, n := range colorNames {
+        if name == n {
+            return Basic(i), nil
+        }
+    }
+    // Six hex digits are RGB even if they are all decimal digits: no color
+    // number is that long.
+    if hex := strings.TrimPrefix(s, "#"); len(hex) == 6 {
+        if n, err := strconv.ParseUint(hex, 16, 32); err == nil {
+            return RGB{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+        }
+    }
+    if n, err := strconv.Atoi(s); err == nil {
+        switch {
+        case n >= 30 && n <= 37:
+            return Basic(n - 30), nil
+        case n >= 90 && n <= 97:
+            return BrightBlack + Basic(n-90), nil
+        case n >= 0 && n <= 255:
+            return Color256(n), nil
+        }
+        return nil, fmt.Errorf("color %q: number out of range", s)
+    }
+    if m := rgbPattern.FindStringSubmatch(s); m != nil {
+        var c [3]uint8
+        for i := range c {
+            n, err := strconv.Atoi(m[i+1])
+            if err != nil || n > 255 {
+                return nil, fmt.Errorf("color %q: component out of range", s)
+            }
+            c[i] = uint8(n)
+        }
+        return RGB{c[0], c[1], c[2]}, nil
+    }
+    return nil, fmt.Errorf("unknown color %q", s)
+}
+
+// colorString returns c in a form ParseANSIColor reads back, "" for nil.
+func colorString(c ANSIColor) string {
+    switch c := c.(type) {
+    case Basic:
+        return colorNames[c%16]
+    case Color256:
+        return "38;5;" + strconv.Itoa(int(c))
+    case RGB:
+        return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+    }
+    return ""
+}
+
+// parseColor returns the color named by s, nil for an empty or invalid s.
+// Options.validate reports invalid colors.
+func parseColor(s string) ANSIColor {
+    if s == "" {
+        return nil
+    }
+    c, _ := ParseANSIColor(s)
+    return c
+}
+
+// colorOr returns c, or fallback if c is nil.
+func colorOr(c, fallback ANSIColor) ANSIColor {
+    if c != nil {
+        return c
+    }
+    return fallback
+}
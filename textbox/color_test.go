@@ -0,0 +1,39 @@
+package textbox
+
+import "testing"
+
+func TestParseANSIColor(t *testing.T) {
+    tests := []struct {
+        s    string
+        want ANSIColor
+    }{
+        {"red", Red},
+        {"Bright-Red", BrightRed},
+        {"31", Red},
+        {"91", BrightRed},
+        {"196", Color256(196)},
+        {"38;5;196", Color256(196)},
+        {"rgb(1,2,3)", RGB{1, 2, 3}},
+        {"#ff0000", RGB{255, 0, 0}},
+        {"ff0000", RGB{255, 0, 0}},
+        {"112233", RGB{0x11, 0x22, 0x33}},
+        {"#112233", RGB{0x11, 0x22, 0x33}},
+        {"000000", RGB{0, 0, 0}},
+        {"999999", RGB{0x99, 0x99, 0x99}},
+    }
+    for _, tt := range tests {
+        got, err := ParseANSIColor(tt.s)
+        if err != nil {
+            t.Errorf("ParseANSIColor(%q): %v", tt.s, err)
+            continue
+        }
+        if got != tt.want {
+            t.Errorf("ParseANSIColor(%q) = %#v, want %#v", tt.s, got, tt.want)
+        }
+    }
+    for _, s := range []string{"256", "1234567", "38;5;300", "rgb(256,0,0)", "#12345", "purpleish"} {
+        if c, err := ParseANSIColor(s); err == nil {
+            t.Errorf("ParseANSIColor(%q) = %#v, want an error", s, c)
+        }
+    }
+}
@@ -0,0 +1,210 @@
+package textbox
+
+import (
+    "fmt"
+    "io"
+    "strings"
+)
+
+// Direction is the axis along which boxes are composed.
+type Direction int
+
+// Directions.
+const (
+    // Horizontal places boxes side by side, left to right.
+    Horizontal Direction = iota
+    // Vertical places boxes one below the other, top to bottom.
+    Vertical
+)
+
+func (d Direction) String() string {
+    switch d {
+    case Horizontal:
+        return "horizontal"
+    case Vertical:
+        return "vertical"
+    }
+    return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// Join writes boxes placed along dir to w, gap columns or rows apart.
+// Horizontally joined boxes are drawn as tall as the tallest of them.
+func Join(w io.Writer, dir Direction, gap int, boxes ...*Box) error {
+    rendered := make([][]string, len(boxes))
+    height := 0
+    for i, b := range boxes {
+        rows, err := b.rows(b.opts)
+        if err != nil {
+            return err
+        }
+        rendered[i] = rows
+        height = max(height, len(rows))
+    }
+
+    switch dir {
+    case Horizontal:
+        for i, b := range boxes {
+            if len(rendered[i]) < height {
+                opts := b.opts
+                opts.Height = height
+                rows, err := b.rows(opts)
+                if err != nil {
+                    return err
+                }
+                rendered[i] = rows
+            }
+        }
+        // Boxes drawn taller, as with a drop shadow below the height, set
+        // the height; rows narrower than their box, as the tab of a title
+        // tab, are filled up so that the boxes beside stay in line.
+        widths := make([]int, len(rendered))
+        height = 0
+        for i, rows := range rendered {
+            widths[i], height = blockWidth(rows), max(height, len(rows))
+        }
+        sep := strings.Repeat(" ", max(gap, 0))
+        for row := 0; row < height; row++ {
+            parts := make([]string, len(rendered))
+            for i, rows := range rendered {
+                s := ""
+                if row < len(rows) {
+                    s = rows[row]
+                }
+                parts[i] = VisualPad(s, widths[i], Left)
+            }
+            if _, err := fmt.Fprintln(w, strings.Join(parts, sep)); err != nil {
+                return err
+            }
+        }
+    case Vertical:
+        for i, rows := range rendered {
+            if i > 0 {
+                if _, err := io.WriteString(w, strings.Repeat("\n", max(gap, 0))); err != nil {
+                    return err
+                }
+            }
+            for _, row := range rows {
+                if _, err := fmt.Fprintln(w, row); err != nil {
+                    return err
+                }
+            }
+        }
+    default:
+        return fmt.Errorf("invalid direction %v", dir)
+    }
+    return nil
+}
+
+// SplitAt splits b before content line row into a top box of lines
+// 0..row-1 and a bottom box of the rest, each with its own border, the
+// inverse of joining them vertically. Both keep the options of b except
+// that the title stays with the top box, the footer goes with the bottom
+// box and a fixed height is dropped. row counts the lines of b, before
+// wrapping or options such as MaxLines. Grids and padded boxes cannot be
+// split.
+func SplitAt(b *Box, row int) (*Box, *Box, error) {
+    if !b.framed() {
+        return nil, nil, fmt.Errorf("only a box framing lines can be split")
+    }
+    if row <= 0 || row >= len(b.lines) {
+        return nil, nil, fmt.Errorf("cannot split a box of %d lines at row %d", len(b.lines), row)
+    }
+    opts := b.opts
+    opts.Height = 0
+    // Rules added to one half must not show up in the other.
+    opts.HighlightRules = opts.HighlightRules[:len(opts.HighlightRules):len(opts.HighlightRules)]
+    top := &Box{lines: append([]string(nil), b.lines[:row]...), opts: opts}
+    bottom := &Box{lines: append([]string(nil), b.lines[row:]...), opts: opts}
+    top.opts.Footer = ""
+    bottom.opts.Title = ""
+    return top, bottom, nil
+}
+
+// ColumnSplit splits b before content column col into a left box of the
+// columns 0..col-1 of every line and a right box of the rest, each with
+// its own border. A wide character across col goes to the right box, and
+// colors running across col continue there. Like SplitAt, the title stays
+// with the left box and the footer goes with the right one; a fixed width
+// is dropped. col counts the columns of the lines of b, before wrapping.
+// Grids and padded boxes cannot be split.
+func ColumnSplit(b *Box, col int) (*Box, *Box, error) {
+    if !b.framed() {
+        return nil, nil, fmt.Errorf("only a box framing lines can be split")
+    }
+    width := 0
+    for _, line := range b.lines {
+        width = max(width, visualLength(line))
+    }
+    if col <= 0 || col >= width {
+        return nil, nil, fmt.Errorf("cannot split a box of %d columns at column %d", width, col)
+    }
+    opts := b.opts
+    opts.Width = 0
+    // Rules added to one half must not show up in the other.
+    opts.HighlightRules = opts.HighlightRules[:len(opts.HighlightRules):len(opts.HighlightRules)]
+    left := &Box{lines: make([]string, len(b.lines)), opts: opts}
+    right := &Box{lines: make([]string, len(b.lines)), opts: opts}
+    for i, line := range b.lines {
+        left.lines[i], right.lines[i] = splitColumn(line, col)
+    }
+    left.opts.Footer = ""
+    right.opts.Title = ""
+    return left, right, nil
+}
+
+// splitColumn cuts line into the cells within the first col columns and
+// the rest.
+func splitColumn(line string, col int) (string, string) {
+    cs := cells(line)
+    n, w := 0, 0
+    for n < len(cs) && w+cs[n].width <= col {
+        w += cs[n].width
+        n++
+    }
+    var wr wrapper
+    wr.add("", cs[:n], "")
+    pieces := wr.last("", cs[n:])
+    return pieces[0], pieces[1]
+}
+
+// rows returns the rows of b drawn with opts. A grid or padded box is drawn
+// as it is, followed by blank rows up to opts.Height.
+func (b *Box) rows(opts Options) ([]string, error) {
+    var rows []string
+    var err error
+    switch {
+    case b.cells != nil:
+        rows, err = b.gridRows()
+    case b.margin != nil:
+        rows, err = b.margin.rows()
+    default:
+        return b.framedRows(opts)
+    }
+    if err != nil || len(rows) >= opts.Height {
+        return rows, err
+    }
+    blankRow := strings.Repeat(" ", blockWidth(rows))
+    for len(rows) < opts.Height {
+        rows = append(rows, blankRow)
+    }
+    return rows, nil
+}
+
+// blockWidth returns the width of the widest of rows.
+func blockWidth(rows []string) int {
+    width := 0
+    for _, row := range rows {
+        width = max(width, visualLength(row))
+    }
+    return width
+}
+
+// framedRows returns the rows of the box b framing its lines, drawn with
+// opts.
+func (b *Box) framedRows(opts Options) ([]string, error) {
+    var buf strings.Builder
+    if err := Render(&buf, b.lines, opts); err != nil {
+        return nil, err
+    }
+    return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
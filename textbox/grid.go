@@ -0,0 +1,74 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Grid arranges rows×cols boxes, given left to right and top to bottom, in
+// a grid without gaps. Every box is drawn as wide as the widest box of its
+// column and as tall as the tallest of its row. The result is a box whose
+// Render draws the whole grid.
+func Grid(rows, cols int, boxes []*Box) (*Box, error) {
+    if rows <= 0 || cols <= 0 {
+        return nil, fmt.Errorf("a grid needs rows and columns, got %d×%d", rows, cols)
+    }
+    if len(boxes) != rows*cols {
+        return nil, fmt.Errorf("a %d×%d grid needs %d boxes, got %d", rows, cols, rows*cols, len(boxes))
+    }
+    cells := make([][]*Box, rows)
+    for r := range cells {
+        cells[r] = append([]*Box(nil), boxes[r*cols:(r+1)*cols]...)
+    }
+    return &Box{cells: cells}, nil
+}
+
+// gridRows returns the rows of the grid b.
+func (b *Box) gridRows() ([]string, error) {
+    rendered := make([][][]string, len(b.cells))
+    widths := make([]int, len(b.cells[0]))
+    heights := make([]int, len(b.cells))
+    for r, row := range b.cells {
+        rendered[r] = make([][]string, len(row))
+        for c, cell := range row {
+            rows, err := cell.rows(cell.opts)
+            if err != nil {
+                return nil, err
+            }
+            rendered[r][c] = rows
+            widths[c] = max(widths[c], blockWidth(rows))
+            heights[r] = max(heights[r], len(rows))
+        }
+    }
+
+    var out []string
+    for r, row := range b.cells {
+        for c, cell := range row {
+            rows := rendered[r][c]
+            if cell.framed() && (blockWidth(rows) < widths[c] || len(rows) < heights[r]) {
+                opts := cell.opts
+                opts.Width, opts.Height = widths[c], heights[r]
+                var err error
+                if rows, err = cell.rows(opts); err != nil {
+                    return nil, err
+                }
+            }
+            rendered[r][c] = rows
+        }
+        // Cells the glyphs cannot size exactly, nested grids and padded
+        // boxes are padded with spaces.
+        for i := 0; i < heights[r]; i++ {
+            var line strings.Builder
+            for c := range row {
+                rows := rendered[r][c]
+                s := ""
+                if i < len(rows) {
+                    s = rows[i]
+                }
+                line.WriteString(s + strings.Repeat(" ", max(widths[c]-visualLength(s), 0)))
+            }
+            out = append(out, line.String())
+        }
+    }
+    return out, nil
+}
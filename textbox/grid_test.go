@@ -0,0 +1,102 @@
+package textbox
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestGrid(t *testing.T) {
+    g, err := Grid(2, 2, []*Box{
+        NewBox([]string{"a"}),
+        NewBox([]string{"wide cell"}),
+        NewBox([]string{"tall", "cell"}),
+        NewBox([]string{"b"}),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := g.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    want := `┌──────┐┌───────────┐
+│ a    ││ wide cell │
+└──────┘└───────────┘
+┌──────┐┌───────────┐
+│ tall ││ b         │
+│ cell ││           │
+└──────┘└───────────┘
+`
+    if buf.String() != want {
+        t.Errorf("grid:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    // A grid is a box and can be a cell of another grid.
+    outer, err := Grid(1, 2, []*Box{g, NewBox([]string{"x"})})
+    if err != nil {
+        t.Fatal(err)
+    }
+    buf.Reset()
+    if err := outer.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    if len(rows) != 7 {
+        t.Errorf("nested grid has %d rows, want 7:\n%s", len(rows), buf.String())
+    }
+    for _, row := range rows {
+        if visualLength(row) != visualLength(rows[0]) {
+            t.Errorf("nested grid row %q is %d wide, want %d", row, visualLength(row), visualLength(rows[0]))
+        }
+    }
+}
+
+func TestGridSize(t *testing.T) {
+    box := NewBox([]string{"x"})
+    for _, tc := range []struct {
+        rows, cols int
+        boxes      []*Box
+    }{
+        {2, 2, []*Box{box, box, box}},
+        {0, 1, nil},
+        {1, -1, nil},
+    } {
+        if _, err := Grid(tc.rows, tc.cols, tc.boxes); err == nil {
+            t.Errorf("Grid(%d, %d) with %d boxes succeeded, want an error", tc.rows, tc.cols, len(tc.boxes))
+        }
+    }
+}
+
+func TestGridTitleTab(t *testing.T) {
+    tab := NewBox([]string{"content"}, WithTitle("T"), func(o *Options) { o.TitleTab = true })
+    g, err := Grid(2, 1, []*Box{tab, NewBox([]string{"x"})})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := g.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    // The column is as wide as the box below its tab, not as the tab.
+    want := "   ┌───┐   \n   │ T │   \n┌──┴───┴──┐\n│ content │\n└─────────┘\n┌─────────┐\n│ x       │\n└─────────┘\n"
+    if buf.String() != want {
+        t.Errorf("grid:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
+
+func TestGridJoinedTaller(t *testing.T) {
+    g, err := Grid(1, 2, []*Box{NewBox([]string{"a"}), NewBox([]string{"b"})})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := Join(&buf, Horizontal, 1, g, NewBox([]string{"1", "2", "3"})); err != nil {
+        t.Fatal(err)
+    }
+    // The grid is padded with blank rows, not repeated bottom borders.
+    want := "┌───┐┌───┐ ┌───┐\n│ a ││ b │ │ 1 │\n└───┘└───┘ │ 2 │\n           │ 3 │\n           └───┘\n"
+    if buf.String() != want {
+        t.Errorf("joined grid:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
@@ -0,0 +1,82 @@
+package textbox
+
+// Dimensions describe the size of a rendered box in columns and rows.
+type Dimensions struct {
+    // Width and Height are the size of the whole box including the border
+    // and the shadow.
+    Width  int
+    Height int
+    // InnerWidth is the width between the left and right border, and
+    // BorderWidth the width of each of them.
+    InnerWidth  int
+    BorderWidth int
+    // LineWidths are the widths of the content rows after wrapping,
+    // numbering and cutting, without padding.
+    LineWidths []int
+    // Rows are the kinds of the rows from top to bottom.
+    Rows []RowKind
+}
+
+// RowKind tells what a row of a box is made of.
+type RowKind string
+
+// Row kinds.
+const (
+    // RowBorder is the top or bottom border.
+    RowBorder RowKind = "border"
+    // RowTab is a row of the title tab above the top border.
+    RowTab RowKind = "tab"
+    // RowContent is a content row between the left and right border.
+    RowContent RowKind = "content"
+    // RowDivider is the divider below the header lines or a section
+    // heading.
+    RowDivider RowKind = "divider"
+    // RowShadow is the row of an inner shadow or the drop shadow below the
+    // box.
+    RowShadow RowKind = "shadow"
+)
+
+// Measure returns the dimensions of the box Render would draw for lines
+// without drawing it. Escape sequences in lines take no room.
+func Measure(lines []string, opts ...Option) (Dimensions, error) {
+    l, err := prepare(lines, NewOptions(opts...))
+    if err != nil {
+        return Dimensions{}, err
+    }
+    d := Dimensions{
+        Width:       l.width(),
+        InnerWidth:  l.innerWidth,
+        BorderWidth: visualLength(l.style.Vertical),
+        LineWidths:  make([]int, len(l.lines)),
+    }
+    if l.titleTab {
+        d.Rows = append(d.Rows, RowTab, RowTab)
+    }
+    d.Rows = append(d.Rows, RowBorder)
+    for range l.titleRows {
+        d.Rows = append(d.Rows, RowBorder)
+    }
+    if l.shadow == InnerShadow {
+        d.Rows = append(d.Rows, RowShadow)
+    }
+    for i, line := range l.lines {
+        for range l.headingsAt(i) {
+            d.Rows = append(d.Rows, RowDivider)
+        }
+        d.LineWidths[i] = visualLength(line)
+        d.Rows = append(d.Rows, RowContent)
+        if i+1 == l.divider && l.hasDivider(len(l.lines)) {
+            d.Rows = append(d.Rows, RowDivider)
+        }
+    }
+    for range l.headingsAt(len(l.lines)) {
+        d.Rows = append(d.Rows, RowDivider)
+    }
+    d.Rows = append(d.Rows, RowBorder)
+    if l.shadow == DropShadow {
+        d.Width += visualLength(shadowGlyph)
+        d.Rows = append(d.Rows, RowShadow)
+    }
+    d.Height = len(d.Rows)
+    return d, nil
+}
@@ -0,0 +1,287 @@
+// Package textbox draws text boxes around lines of text.
+package textbox
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strings"
+)
+
+// Options configure how a box is drawn. The JSON form is used for
+// configuration files.
+type Options struct {
+    // Style selects the frame by number or name.
+    Style string `json:"style"`
+    // TitleStyle draws the top border with the title in another style,
+    // given like Style, joined to the sides of the box. Empty draws the
+    // whole box in Style.
+    TitleStyle string `json:"title_style"`
+    // ColumnAlign places the cells of table columns, one alignment per
+    // column. Columns beyond the list are aligned left.
+    ColumnAlign []Alignment `json:"column_align"`
+    // TableHeader sets the first table row apart as column headers by a
+    // divider joined to the column lines. Unless NoColor is set, the
+    // headers are bold with HeaderBold and colored in HeaderColor.
+    TableHeader bool   `json:"table_header"`
+    HeaderBold  bool   `json:"header_bold"`
+    HeaderColor string `json:"header_color"`
+    // CellWrap narrows the columns of a table to fit Width, widest first,
+    // and wraps the cells too wide for them in WrapMode, so that a row
+    // takes several lines. Without it rows wider than Width are cut.
+    CellWrap bool `json:"cell_wrap"`
+    // ColumnMaxWidth caps the width of every table column and
+    // ColumnMaxWidths that of the columns they number from 1, overriding
+    // it. Cells wider than their column wrap onto further rows; 0 leaves
+    // the width alone.
+    ColumnMaxWidth  int         `json:"column_max_width"`
+    ColumnMaxWidths map[int]int `json:"column_max_widths"`
+    // Char is the glyph used for every frame component with style 4.
+    Char string `json:"char"`
+    // FillBlock draws the horizontal borders as a solid block bar.
+    FillBlock bool `json:"fill_block"`
+    // Theme selects a registered theme, which replaces Style and Char and
+    // colors the box. BorderColor and TitleColor override its colors.
+    Theme string `json:"theme"`
+    // NoColor drops all colors and text attributes, including those of
+    // the theme.
+    NoColor bool `json:"no_color"`
+    // ASCII replaces the border glyphs with ASCII characters for terminals
+    // that cannot show box drawing characters.
+    ASCII bool `json:"ascii"`
+    // AutoStyle switches to a style whose border divides Width evenly.
+    AutoStyle bool `json:"auto_style"`
+    // InnerBorder draws a second, style 1 border one space inside the frame.
+    InnerBorder bool `json:"inner_border"`
+    // Shadow draws a shadow with the box. NoShadow keeps the shadow of the
+    // theme, if any; the theme's is always a DropShadow.
+    Shadow ShadowStyle `json:"shadow"`
+    // BorderColor colors the border. It takes any color ParseANSIColor
+    // accepts.
+    BorderColor string `json:"border_color"`
+
+    // Title is embedded in the top border.
+    Title string `json:"title"`
+    // TitleAlign places the title at the left, center or right of the top
+    // border. Boxes without lines use TitleOnlyAlign instead.
+    TitleAlign     Alignment `json:"title_align"`
+    TitleOnlyAlign Alignment `json:"align_title"`
+    // Footer is embedded in the bottom border, placed according to
+    // FooterAlign like the title.
+    Footer      string    `json:"footer"`
+    FooterAlign Alignment `json:"footer_align"`
+    // TitleUnderline underlines the title text, leaving its caps alone.
+    TitleUnderline bool `json:"title_underline"`
+    // TitleColor colors the title text like BorderColor the border.
+    TitleColor string `json:"title_color"`
+    // TitleMinBody is the minimum interior width of the box.
+    TitleMinBody int `json:"title_min_body"`
+    // TitleFit widens the box for a title wider than the content or cuts
+    // the title to the width of the box.
+    TitleFit TitleFit `json:"title_fit"`
+    // TitleWrap continues a title too wide for Width on further rows of
+    // the top border instead of widening the box.
+    TitleWrap bool `json:"title_wrap"`
+    // TitleTab draws the title in a tab standing on the top border, like
+    // the tab of a file folder, placed by TitleAlign.
+    TitleTab bool `json:"title_tab"`
+
+    // Width is the total width of the box. Zero sizes the box to its
+    // content; longer lines are cut.
+    Width int `json:"width"`
+    // Height is the total height of the box. Zero sizes the box to its
+    // content; further lines are cut.
+    Height int `json:"height"`
+    // Wrap breaks lines wider than Wrap columns. Hard WrapMode ends broken
+    // lines with ContinueChar. Continuation lines are indented by
+    // WrapIndent spaces.
+    Wrap         int      `json:"wrap"`
+    WrapMode     WrapMode `json:"wrap_mode"`
+    WrapIndent   int      `json:"wrap_indent"`
+    ContinueChar string   `json:"continue_char"`
+    // PreserveIndent starts continuation lines with the leading whitespace
+    // of the broken line, before the WrapIndent spaces.
+    PreserveIndent bool `json:"preserve_indent"`
+    // Hyphenate ends the pieces of words broken by soft wrapping with a
+    // hyphen.
+    Hyphenate bool `json:"hyphenate"`
+    // Overflow selects what happens to lines wider than Width and rows
+    // beyond Height.
+    Overflow OverflowMode `json:"overflow"`
+    // Reflow picks the narrowest wrap width at which the content still fits
+    // into the rows of a box of Width and Height, evening out the lines.
+    Reflow bool `json:"reflow"`
+
+    // TitleSep draws a divider below the first HeaderLines lines, setting
+    // a header apart from the body.
+    TitleSep    bool `json:"title_sep"`
+    HeaderLines int  `json:"header_lines"`
+    // SectionMarker starts the lines drawn as section headings: each is
+    // a divider labeled with the rest of the line, like the title in the
+    // top border. Empty draws every line as content.
+    SectionMarker string `json:"section_marker"`
+
+    // ControlChars replaces control characters other than tab and ESC
+    // before measuring: "strip" removes them, "caret" writes ^G,
+    // "pictures" ␇. "keep" leaves them.
+    ControlChars string `json:"control_chars"`
+    // Sanitize removes escape sequences and control characters from the
+    // lines, title and footer: "strict" all of them, "sgr" all but colors
+    // and text attributes. Empty or "off" keeps the text as is.
+    Sanitize string `json:"sanitize"`
+    // Normalize applies the Unicode normalization form "nfc" or "nfkc" to
+    // the lines, title and footer before they are measured. Empty or
+    // "none" keeps their bytes.
+    Normalize string `json:"normalize"`
+
+    // HighlightRules color the content lines they match, taking priority
+    // over the rules of the theme.
+    HighlightRules []HighlightRule `json:"-"`
+
+    // Reverse reverses the order of the lines. MaxLines keeps only the
+    // first MaxLines lines, followed by a row counting the others. With
+    // Collapse, more than MaxLines lines collapse into a box holding only
+    // the title and the line count.
+    Reverse  bool `json:"reverse"`
+    MaxLines int  `json:"max_lines"`
+    Collapse bool `json:"collapse"`
+
+    // TextAlign aligns the lines. Justify leaves the last line aligned
+    // left. Center is a shorthand for the Center alignment.
+    TextAlign Alignment `json:"align"`
+    Center    bool      `json:"center"`
+    // Tree draws the lines indented by multiples of TreeIndent columns as
+    // boxes nested in the box of the lines above them. A tab counts as one
+    // level.
+    Tree       bool `json:"tree"`
+    TreeIndent int  `json:"tree_indent"`
+    // Vertical writes one character per line in a box one glyph wide.
+    Vertical bool `json:"vertical"`
+    // LineNumbers prefixes every line with its number, written in
+    // superscript or subscript digits if requested.
+    LineNumbers bool `json:"line_numbers"`
+    Superscript bool `json:"superscript"`
+    Subscript   bool `json:"subscript"`
+    // Overlay moves the cursor over padding instead of printing spaces so
+    // that content already on the screen shows through.
+    Overlay bool `json:"overlay"`
+
+    // Progress adds a bar below the lines filled to the fraction Progress
+    // of the interior with ProgressFill glyphs, the rest with ProgressEmpty
+    // glyphs and, with ProgressLabel, followed by the percentage. Negative
+    // values draw no bar.
+    Progress      float64 `json:"progress"`
+    ProgressFill  string  `json:"progress_fill"`
+    ProgressEmpty string  `json:"progress_empty"`
+    ProgressLabel bool    `json:"progress_label"`
+
+    // Debug receives sizing diagnostics when set.
+    Debug io.Writer `json:"-"`
+}
+
+// DefaultOptions returns the options used when nothing else is configured.
+func DefaultOptions() Options {
+    return Options{Style: "1", TitleAlign: Center, TitleOnlyAlign: Center, FooterAlign: Center, HeaderLines: 1, TreeIndent: 2, ControlChars: "pictures", WrapMode: Soft, ContinueChar: `\`, Progress: -1, ProgressFill: "█", ProgressEmpty: "░"}
+}
+
+// Option changes one aspect of Options.
+type Option func(*Options)
+
+// NewOptions returns the default options with opts applied in order.
+func NewOptions(opts ...Option) Options {
+    o := DefaultOptions()
+    for _, opt := range opts {
+        opt(&o)
+    }
+    return o
+}
+
+// WithOptions replaces all options with o.
+func WithOptions(o Options) Option {
+    return func(opts *Options) { *opts = o }
+}
+
+// WithStyle selects the style by name or number.
+func WithStyle(name string) Option {
+    return func(opts *Options) { opts.Style = name }
+}
+
+// WithTitle sets the title embedded in the top border.
+func WithTitle(title string) Option {
+    return func(opts *Options) { opts.Title = title }
+}
+
+// WithWidth sets the total width of the box.
+func WithWidth(width int) Option {
+    return func(opts *Options) { opts.Width = width }
+}
+
+// WithHeight sets the total height of the box.
+func WithHeight(height int) Option {
+    return func(opts *Options) { opts.Height = height }
+}
+
+// WithWrap breaks lines wider than width columns according to mode.
+func WithWrap(width int, mode WrapMode) Option {
+    return func(opts *Options) { opts.Wrap, opts.WrapMode = width, mode }
+}
+
+// LoadOptions reads a configuration file. Fields missing from the file keep
+// their default value.
+func LoadOptions(path string) (Options, error) {
+    opts := DefaultOptions()
+    _, err := opts.Load(path)
+    return opts, err
+}
+
+// renamedKeys maps former configuration keys to the keys replacing them.
+var renamedKeys = map[string]string{
+    "align_content": "align",
+}
+
+// Load decodes a configuration file over o and returns the keys the file
+// set. Files ending in .toml are read as TOML, all others as JSON; the keys
+// are the JSON names of the fields in both cases. Renamed keys are still
+// read and reported under their new name.
+func (o *Options) Load(path string) ([]string, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    if strings.HasSuffix(path, ".toml") {
+        values, err := parseTOML(string(data))
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+        if data, err = json.Marshal(values); err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+    }
+
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(data, &fields); err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    for old, key := range renamedKeys {
+        if value, ok := fields[old]; ok {
+            delete(fields, old)
+            if _, ok := fields[key]; !ok {
+                fields[key] = value
+            }
+            if data, err = json.Marshal(fields); err != nil {
+                return nil, fmt.Errorf("%s: %w", path, err)
+            }
+        }
+    }
+    if err := json.Unmarshal(data, o); err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys, nil
+}
@@ -0,0 +1,61 @@
+package textbox
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestLoadRenamedKeys(t *testing.T) {
+    dir := t.TempDir()
+    for _, tc := range []struct {
+        name, data string
+        want       Alignment
+    }{
+        {"old.json", `{"align_content": "right"}`, Right},
+        {"old.toml", "align_content = \"center\"\n", Center},
+        {"both.json", `{"align_content": "right", "align": "justify"}`, Justify},
+    } {
+        path := filepath.Join(dir, tc.name)
+        if err := os.WriteFile(path, []byte(tc.data), 0o644); err != nil {
+            t.Fatal(err)
+        }
+        opts := DefaultOptions()
+        keys, err := opts.Load(path)
+        if err != nil {
+            t.Errorf("Load(%s): %v", tc.name, err)
+            continue
+        }
+        if opts.TextAlign != tc.want {
+            t.Errorf("Load(%s): TextAlign = %v, want %v", tc.name, opts.TextAlign, tc.want)
+        }
+        if want := []string{"align"}; !reflect.DeepEqual(keys, want) {
+            t.Errorf("Load(%s) keys = %q, want %q", tc.name, keys, want)
+        }
+    }
+}
+
+func TestLoadTOMLArraysAndTables(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "config.toml")
+    data := "column_align = [\"right\", 'center',] # last\ncolumn_max_widths = {2 = 30, \"3\" = 4}\n"
+    if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    opts := DefaultOptions()
+    if _, err := opts.Load(path); err != nil {
+        t.Fatal(err)
+    }
+    if want := []Alignment{Right, Center}; !reflect.DeepEqual(opts.ColumnAlign, want) {
+        t.Errorf("ColumnAlign = %v, want %v", opts.ColumnAlign, want)
+    }
+    if want := map[int]int{2: 30, 3: 4}; !reflect.DeepEqual(opts.ColumnMaxWidths, want) {
+        t.Errorf("ColumnMaxWidths = %v, want %v", opts.ColumnMaxWidths, want)
+    }
+
+    for _, value := range []string{"[[1]]", "{a}", "[1, {b = 2}]"} {
+        if _, err := parseTOMLValue(value); err == nil {
+            t.Errorf("parseTOMLValue(%s) succeeded, want an error", value)
+        }
+    }
+}
@@ -0,0 +1,67 @@
+package textbox
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// OverflowMode selects what happens to content that does not fit into a box
+// of Options.Width or Options.Height.
+type OverflowMode int
+
+// Overflow modes. The names carry a prefix as Wrap is taken by the function.
+const (
+    // OverflowClip cuts the lines and rows that do not fit.
+    OverflowClip OverflowMode = iota
+    // OverflowWrap soft wraps lines wider than the box. Rows that still do
+    // not fit are cut.
+    OverflowWrap
+    // OverflowError makes Render fail with ErrOverflow.
+    OverflowError
+)
+
+// ErrOverflow is returned for content that does not fit with OverflowError.
+var ErrOverflow = errors.New("content does not fit")
+
+// overflowNames are the names of the overflow modes, indexed by mode.
+var overflowNames = []string{"clip", "wrap", "error"}
+
+func (m OverflowMode) String() string {
+    if m >= 0 && int(m) < len(overflowNames) {
+        return overflowNames[m]
+    }
+    return fmt.Sprintf("OverflowMode(%d)", int(m))
+}
+
+// ParseOverflowMode returns the overflow mode named clip, wrap or error.
+func ParseOverflowMode(name string) (OverflowMode, error) {
+    for i, n := range overflowNames {
+        if strings.EqualFold(name, n) {
+            return OverflowMode(i), nil
+        }
+    }
+    return OverflowClip, fmt.Errorf("invalid overflow mode %q, use clip, wrap or error", name)
+}
+
+// MarshalText encodes the mode as its name.
+func (m OverflowMode) MarshalText() ([]byte, error) {
+    if m < 0 || int(m) >= len(overflowNames) {
+        return nil, fmt.Errorf("invalid overflow mode %d", int(m))
+    }
+    return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes an overflow mode name.
+func (m *OverflowMode) UnmarshalText(text []byte) error {
+    parsed, err := ParseOverflowMode(string(text))
+    if err == nil {
+        *m = parsed
+    }
+    return err
+}
+
+// Set implements flag.Value.
+func (m *OverflowMode) Set(name string) error {
+    return m.UnmarshalText([]byte(name))
+}
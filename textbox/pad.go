@@ -0,0 +1,64 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// margin is blank space around a box.
+type margin struct {
+    box                      *Box
+    top, right, bottom, left int
+}
+
+// Pad returns b with top blank rows above it, bottom blank rows below it
+// and left and right columns of spaces on either side, all outside its
+// border. The margin is part of what the result draws, not a new frame;
+// negative sizes count as 0. Changes to b show in the padded box.
+func Pad(b *Box, top, right, bottom, left int) *Box {
+    return &Box{margin: &margin{
+        box: b,
+        top: max(top, 0), right: max(right, 0), bottom: max(bottom, 0), left: max(left, 0),
+    }}
+}
+
+// CenterBox returns b centered in a block of totalWidth columns and
+// totalHeight rows, with spaces and blank lines around it. An odd column or
+// row left over goes right of or below b. The margin is sized for b as it
+// is drawn now; CenterBox fails if b does not fit.
+func CenterBox(b *Box, totalWidth, totalHeight int) (*Box, error) {
+    rows, err := b.rows(b.opts)
+    if err != nil {
+        return nil, err
+    }
+    width := blockWidth(rows)
+    if width > totalWidth || len(rows) > totalHeight {
+        return nil, fmt.Errorf("a box of %d×%d does not fit into %d×%d", width, len(rows), totalWidth, totalHeight)
+    }
+    left, top := (totalWidth-width)/2, (totalHeight-len(rows))/2
+    return Pad(b, top, totalWidth-width-left, totalHeight-len(rows)-top, left), nil
+}
+
+// rows returns the rows of the box with the margin around them.
+func (m *margin) rows() ([]string, error) {
+    rows, err := m.box.rows(m.box.opts)
+    if err != nil {
+        return nil, err
+    }
+    width := blockWidth(rows)
+    blankRow := strings.Repeat(" ", m.left+width+m.right)
+    out := make([]string, 0, m.top+len(rows)+m.bottom)
+    for range m.top {
+        out = append(out, blankRow)
+    }
+    left, right := strings.Repeat(" ", m.left), strings.Repeat(" ", m.right)
+    for _, row := range rows {
+        // Rows narrower than the box, as next to a drop shadow, are
+        // filled up.
+        out = append(out, left+row+strings.Repeat(" ", width-visualLength(row))+right)
+    }
+    for range m.bottom {
+        out = append(out, blankRow)
+    }
+    return out, nil
+}
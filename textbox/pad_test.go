@@ -0,0 +1,77 @@
+package textbox
+
+import (
+    "bytes"
+    "testing"
+)
+
+func TestPad(t *testing.T) {
+    b := NewBox([]string{"x"})
+    var buf bytes.Buffer
+    if err := Pad(b, 1, 2, 1, 3).Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    want := "          \n" +
+        "   ┌───┐  \n" +
+        "   │ x │  \n" +
+        "   └───┘  \n" +
+        "          \n"
+    if buf.String() != want {
+        t.Errorf("padded box = %q, want %q", buf.String(), want)
+    }
+
+    // Negative sizes add nothing.
+    buf.Reset()
+    if err := Pad(b, -1, -1, -1, -1).Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    if want := "┌───┐\n│ x │\n└───┘\n"; buf.String() != want {
+        t.Errorf("box padded by negative sizes = %q, want %q", buf.String(), want)
+    }
+
+    // Padding sets grid cells apart.
+    g, err := Grid(1, 2, []*Box{Pad(b, 0, 1, 0, 0), b})
+    if err != nil {
+        t.Fatal(err)
+    }
+    buf.Reset()
+    if err := g.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    if want := "┌───┐ ┌───┐\n│ x │ │ x │\n└───┘ └───┘\n"; buf.String() != want {
+        t.Errorf("grid of padded boxes = %q, want %q", buf.String(), want)
+    }
+    if _, _, err := SplitAt(Pad(b, 1, 1, 1, 1), 1); err == nil {
+        t.Error("SplitAt of a padded box succeeded, want an error")
+    }
+}
+
+func TestCenterBox(t *testing.T) {
+    b := NewBox([]string{"x"})
+    c, err := CenterBox(b, 10, 6)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := c.Render(&buf); err != nil {
+        t.Fatal(err)
+    }
+    want := "          \n" +
+        "  ┌───┐   \n" +
+        "  │ x │   \n" +
+        "  └───┘   \n" +
+        "          \n" +
+        "          \n"
+    if buf.String() != want {
+        t.Errorf("centered box = %q, want %q", buf.String(), want)
+    }
+
+    if _, err := CenterBox(b, 5, 3); err != nil {
+        t.Errorf("box filling the block exactly: %v", err)
+    }
+    for _, size := range [][2]int{{4, 3}, {5, 2}} {
+        if _, err := CenterBox(b, size[0], size[1]); err == nil {
+            t.Errorf("5×3 box centered in %d×%d succeeded, want an error", size[0], size[1])
+        }
+    }
+}
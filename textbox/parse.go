@@ -0,0 +1,137 @@
+package textbox
+
+import (
+    "errors"
+    "strings"
+)
+
+// ParseBox reads a box drawn by Render back into a Box: its lines, the
+// style, found among the registered styles or made up of the glyphs of the
+// border, the title and footer with their placement and a divider below
+// header lines. Escape sequences are dropped; sizes, the alignment of the
+// lines, shadows, title tabs and inner borders are not recovered, nor the
+// title caps of unregistered styles.
+func ParseBox(s string) (*Box, error) {
+    rows := strings.Split(strings.TrimRight(stripANSI(s), "\n"), "\n")
+    if len(rows) < 2 {
+        return nil, errors.New("a box needs a top and a bottom border")
+    }
+    names := Styles()
+    var styles []BoxStyle
+    for _, name := range names {
+        style, _ := LookupStyle(name)
+        styles = append(styles, style)
+    }
+    if style, ok := borderStyle(rows); ok {
+        names, styles = append(names, style.String()), append(styles, style)
+    }
+    for i, style := range styles {
+        if b, ok := parseRows(rows, style); ok {
+            b.opts.Style = names[i]
+            return b, nil
+        }
+    }
+    return nil, errors.New("the border matches no style")
+}
+
+// borderStyle makes up a style of the first and last glyphs of the borders
+// in rows, the second glyph of the bottom or top border and the first of
+// the row below the top. The title caps are the bottom corners, as in the
+// built-in styles.
+func borderStyle(rows []string) (BoxStyle, bool) {
+    top, bottom := cells(rows[0]), cells(rows[len(rows)-1])
+    if len(top) < 2 || len(bottom) < 2 {
+        return BoxStyle{}, false
+    }
+    // A title or footer may start right after the corner.
+    horizontal, vertical := top[0].s, top[0].s
+    if len(bottom) > 2 && bottom[1].s != " " {
+        horizontal = bottom[1].s
+    } else if len(top) > 2 {
+        horizontal = top[1].s
+    }
+    if len(rows) > 2 {
+        if row := cells(rows[1]); len(row) > 0 {
+            vertical = row[0].s
+        }
+    }
+    style, err := NewBoxStyle(top[0].s, top[len(top)-1].s, bottom[0].s, bottom[len(bottom)-1].s,
+        horizontal, vertical, bottom[len(bottom)-1].s, bottom[0].s)
+    return style, err == nil
+}
+
+// parseRows reads rows as a box drawn with style.
+func parseRows(rows []string, style BoxStyle) (*Box, bool) {
+    top, ok := between(rows[0], style.TopLeft, style.TopRight)
+    if !ok {
+        return nil, false
+    }
+    bottom, ok := between(rows[len(rows)-1], style.BottomLeft, style.BottomRight)
+    if !ok {
+        return nil, false
+    }
+    opts := DefaultOptions()
+    if opts.Title, opts.TitleAlign, ok = parseDecor(top, style.Horizontal, style.TitleLeft+" ", " "+style.TitleRight); !ok {
+        return nil, false
+    }
+    opts.TitleOnlyAlign = opts.TitleAlign
+    if opts.Footer, opts.FooterAlign, ok = parseDecor(bottom, style.Horizontal, " ", " "); !ok {
+        return nil, false
+    }
+    // Columns the glyphs cannot fill widen the footer.
+    opts.Footer = strings.TrimRight(opts.Footer, " ")
+
+    var lines []string
+    left, right := teeGlyphs(style)
+    for _, row := range rows[1 : len(rows)-1] {
+        if inner, ok := between(row, left, right); ok && inner != "" && strings.Trim(inner, style.Horizontal) == "" {
+            if !opts.TitleSep {
+                opts.TitleSep, opts.HeaderLines = true, len(lines)
+            }
+            continue
+        }
+        inner, ok := between(row, style.Vertical, style.Vertical)
+        if !ok {
+            return nil, false
+        }
+        lines = append(lines, strings.TrimRight(strings.TrimPrefix(inner, " "), " "))
+    }
+    return &Box{lines: lines, opts: opts}, true
+}
+
+// between returns s without prefix and suffix, and whether s has both.
+func between(s, prefix, suffix string) (string, bool) {
+    if len(s) < len(prefix)+len(suffix) || !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, suffix) {
+        return "", false
+    }
+    return s[len(prefix) : len(s)-len(suffix)], true
+}
+
+// parseDecor reads the text embedded between open and close in the border
+// interior s, which is otherwise made of fill glyphs, and where it is
+// placed.
+func parseDecor(s, fill, open, close string) (string, Alignment, bool) {
+    var leftFill, rightFill int
+    for ; strings.HasPrefix(s, fill); leftFill++ {
+        s = s[len(fill):]
+    }
+    for ; s != "" && strings.HasSuffix(s, fill); rightFill++ {
+        s = s[:len(s)-len(fill)]
+    }
+    if s == "" {
+        return "", Center, true
+    }
+    text, ok := between(s, open, close)
+    if !ok {
+        return "", Center, false
+    }
+    // Text aligned left or right keeps one fill glyph to the corner, and
+    // centered text differs by at most one glyph between the sides.
+    switch {
+    case leftFill <= 1 && rightFill > leftFill+1:
+        return text, Left, true
+    case rightFill <= 1 && leftFill > rightFill+1:
+        return text, Right, true
+    }
+    return text, Center, true
+}
@@ -0,0 +1,85 @@
+package textbox
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+func TestParseBoxRoundTrip(t *testing.T) {
+    tests := []struct {
+        name  string
+        lines []string
+        opts  []Option
+    }{
+        {"plain", []string{"hello", "world"}, nil},
+        {"empty", nil, nil},
+        {"title", []string{"hello"}, []Option{WithTitle("Notes")}},
+        {"title left", []string{"a wide line"}, []Option{WithTitle("T"), func(o *Options) { o.TitleAlign = Left }}},
+        {"title right", []string{"a wide line"}, []Option{WithTitle("T"), func(o *Options) { o.TitleAlign = Right }}},
+        {"title only", nil, []Option{WithTitle("Only")}},
+        {"footer", []string{"a wide line"}, []Option{func(o *Options) { o.Footer, o.FooterAlign = "f", Right }}},
+        {"divider", []string{"head", "body", "more"}, []Option{func(o *Options) { o.TitleSep, o.HeaderLines = true, 2 }}},
+        {"double", []string{"x"}, []Option{WithStyle("double"), WithTitle("D")}},
+        {"notation", []string{"x"}, []Option{WithStyle("TL:+ TR:+ BL:+ BR:+ H:= V:! TitleL:+ TitleR:+"), WithTitle("N")}},
+        {"custom", []string{"x"}, []Option{WithStyle(CustomStyle), func(o *Options) { o.Char = "#" }}},
+        {"colored", []string{"x"}, []Option{WithTitle("C"), func(o *Options) { o.BorderColor = "red" }}},
+    }
+    for _, tt := range tests {
+        want, err := NewBox(tt.lines, tt.opts...).MarshalText()
+        if err != nil {
+            t.Fatal(err)
+        }
+        var b Box
+        if err := b.UnmarshalText(want); err != nil {
+            t.Errorf("%s: UnmarshalText(%q): %v", tt.name, want, err)
+            continue
+        }
+        b.opts.BorderColor = NewOptions(tt.opts...).BorderColor
+        got, err := b.MarshalText()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if string(got) != string(want) {
+            t.Errorf("%s: round trip =\n%s\nwant:\n%s", tt.name, got, want)
+        }
+    }
+}
+
+func TestParseBoxContent(t *testing.T) {
+    b, err := ParseBox("┌┘ Title └───┐\n│ one         │\n├─────────────┤\n│ two         │\n└─── foot ────┘\n")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := b.Lines(); len(got) != 2 || got[0] != "one" || got[1] != "two" {
+        t.Errorf("Lines = %q, want one and two", got)
+    }
+    if b.Title() != "Title" || b.opts.TitleAlign != Left || b.opts.Footer != "foot" || b.opts.Style != "single" {
+        t.Errorf("title %q %v, footer %q, style %q", b.Title(), b.opts.TitleAlign, b.opts.Footer, b.opts.Style)
+    }
+    if !b.opts.TitleSep || b.opts.HeaderLines != 1 {
+        t.Errorf("TitleSep %v, HeaderLines %d, want a divider after 1 line", b.opts.TitleSep, b.opts.HeaderLines)
+    }
+
+    for _, s := range []string{"", "┌──┐", "┌──┐\n│ x\n└──┘"} {
+        if _, err := ParseBox(s); err == nil {
+            t.Errorf("ParseBox(%q) succeeded, want an error", s)
+        }
+    }
+}
+
+func TestBoxInJSON(t *testing.T) {
+    type fixture struct {
+        Box *Box `json:"box"`
+    }
+    data, err := json.Marshal(fixture{NewBox([]string{"x"}, WithTitle("J"))})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var f fixture
+    if err := json.Unmarshal(data, &f); err != nil {
+        t.Fatal(err)
+    }
+    if f.Box.Title() != "J" || len(f.Box.Lines()) != 1 || f.Box.Lines()[0] != "x" {
+        t.Errorf("decoded %s into title %q and lines %q", data, f.Box.Title(), f.Box.Lines())
+    }
+}
@@ -0,0 +1,31 @@
+package textbox
+
+import (
+    "fmt"
+    "math"
+    "strings"
+)
+
+// progressMinWidth is the narrowest progress bar, in columns, drawn in a
+// box that is not otherwise wider.
+const progressMinWidth = 10
+
+// progressLabel returns the percentage written after the bar, if any.
+func (o Options) progressLabel() string {
+    if !o.ProgressLabel {
+        return ""
+    }
+    return fmt.Sprintf(" %3.0f%%", math.Min(o.Progress, 1)*100)
+}
+
+// progressBar returns a bar width columns wide, including its label, filled
+// in proportion to o.Progress. Columns the glyphs cannot fill are blank.
+func progressBar(width int, o Options) string {
+    label := o.progressLabel()
+    width = max(width-visualLength(label), 0)
+    fill, empty := visualLength(o.ProgressFill), visualLength(o.ProgressEmpty)
+    filled := int(math.Round(float64(width)*math.Min(o.Progress, 1))) / fill
+    rest := (width - filled*fill) / empty
+    gap := width - filled*fill - rest*empty
+    return strings.Repeat(o.ProgressFill, filled) + strings.Repeat(o.ProgressEmpty, rest) + strings.Repeat(" ", gap) + label
+}
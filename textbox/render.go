@@ -0,0 +1,761 @@
+package textbox
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+)
+
+// visualLength returns the visual width of the string considering the character widths in different writing systems.
+// Grapheme clusters such as emoji sequences and flags are measured as a whole; escape sequences have no width.
+func visualLength(s string) int {
+    return stringWidth(stripANSI(s))
+}
+
+// VisualWidth returns the number of columns s takes on a terminal, measured
+// like the lines of a box.
+func VisualWidth(s string) int {
+    return visualLength(s)
+}
+
+// max returns the larger of two integers.
+func max(a, b int) int {
+    if a > b {
+        return a
+    }
+    return b
+}
+
+func repeatChar(char string, count int) string {
+    result := ""
+    for i := 0; i < count; i++ {
+        result += char
+    }
+    return result
+}
+
+// Render writes lines framed according to opts to w.
+func Render(w io.Writer, lines []string, opts Options) error {
+    if len(lines) == 0 {
+        // Only here is a box without lines known to hold only the title:
+        // the lines of a Streamer are still to come.
+        opts.TitleAlign = opts.TitleOnlyAlign
+    }
+    l, err := prepare(lines, opts)
+    if err != nil {
+        return err
+    }
+    return l.draw(w)
+}
+
+// prepare checks opts, selects the style and sizes the box for lines. Render
+// and Measure share it so that they always agree.
+func prepare(lines []string, opts Options) (boxLayout, error) {
+    if err := opts.validate(); err != nil {
+        return boxLayout{}, err
+    }
+    lines = opts.sanitize(lines)
+    style, err := opts.style()
+    if err != nil {
+        return boxLayout{}, err
+    }
+    if opts.AutoStyle {
+        style = autoStyle(style, opts)
+    }
+    debugStyle(opts.Debug, style)
+    if _, err := opts.topStyle(style); err != nil {
+        return boxLayout{}, err
+    }
+    if opts.Reflow {
+        if opts.Wrap, err = reflowWidth(lines, style, opts); err != nil {
+            return boxLayout{}, err
+        }
+        opts.WrapMode = Soft
+        debugf(opts.Debug, "reflow: wrap %d", opts.Wrap)
+    }
+    if opts.Overflow == OverflowWrap && opts.Width > 0 {
+        avail := max(opts.Width-2*visualLength(style.Vertical)-2, 1)
+        if opts.Wrap <= 0 || opts.Wrap > avail {
+            opts.Wrap = avail
+        }
+    }
+    l := layoutBox(lines, style, opts)
+    if opts.Overflow == OverflowError && l.overflow != nil {
+        return boxLayout{}, l.overflow
+    }
+    return l, nil
+}
+
+// debugf writes one diagnostic line to w if it is set.
+func debugf(w io.Writer, format string, args ...any) {
+    if w != nil {
+        fmt.Fprintf(w, "debug: "+format+"\n", args...)
+    }
+}
+
+// debugStyle reports the glyphs of the effective style and their widths.
+func debugStyle(w io.Writer, style BoxStyle) {
+    names := []string{"TopLeft", "TopRight", "BottomLeft", "BottomRight", "Horizontal", "Vertical", "TitleLeft", "TitleRight"}
+    var parts []string
+    for i, g := range style.Glyphs() {
+        parts = append(parts, fmt.Sprintf("%s=%q(%d)", names[i], g, visualLength(g)))
+    }
+    debugf(w, "style: %s", strings.Join(parts, " "))
+}
+
+// fitWidth grows innerWidth so that the horizontal borders can be drawn
+// from whole glyphs: it is at least one glyph (or the title decoration of
+// width fixed) wide and a multiple of the glyph width. A decoration of
+// another parity is padded with a space where it is drawn.
+func fitWidth(innerWidth, fixed, glyphWidth int) int {
+    // Styles are checked for glyphs without width, but stay safe.
+    glyphWidth = max(glyphWidth, 1)
+    innerWidth = max(innerWidth, max(fixed, glyphWidth))
+    if rest := innerWidth % glyphWidth; rest != 0 {
+        innerWidth += glyphWidth - rest
+    }
+    return innerWidth
+}
+
+// blank returns n columns of padding: spaces, or with overlay a cursor
+// movement past the cells so that existing screen content shows through.
+func blank(n int, overlay bool) string {
+    if !overlay {
+        return strings.Repeat(" ", n)
+    }
+    if n == 0 {
+        return ""
+    }
+    return fmt.Sprintf("\x1b[%dC", n)
+}
+
+// blockFill is the horizontal border glyph used with --fill-block.
+const blockFill = "█"
+
+// titleDecoration returns the title framed by the style's title caps.
+func titleDecoration(style BoxStyle, title string) string {
+    return style.TitleLeft + " " + title + " " + style.TitleRight
+}
+
+// innerBorderLines frames lines with a style 1 border that, once framed by
+// outer, sits one space inside the outer border on every side.
+func innerBorderLines(lines []string, outer BoxStyle, opts Options) []string {
+    inner := opts
+    inner.Title, inner.Footer, inner.FillBlock, inner.InnerBorder = "", "", false, false
+    if opts.Title != "" {
+        // The title keeps a fill glyph between it and each corner.
+        framed := visualLength(titleDecoration(outer, opts.Title)) + 2*visualLength(outer.Horizontal)
+        inner.TitleMinBody = max(inner.TitleMinBody, framed)
+    }
+    // The inner border and the gaps take four columns of the outer interior.
+    inner.TitleMinBody -= 4
+    if opts.Width > 0 {
+        inner.Width = opts.Width - 2*visualLength(outer.Vertical) - 2
+    }
+    if opts.Height > 0 {
+        inner.Height = max(opts.Height-4, 2)
+    }
+
+    var buf strings.Builder
+    drawBox(&buf, lines, builtinStyles[0].style, inner)
+    framed := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    return append(append([]string{""}, framed...), "")
+}
+
+// Digit sets for line numbers.
+var (
+    superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+    subscriptDigits   = []rune("₀₁₂₃₄₅₆₇₈₉")
+)
+
+// numberLines prefixes every line with its right aligned line number.
+func numberLines(lines []string, opts Options) []string {
+    digits := len(fmt.Sprint(len(lines)))
+    numbered := make([]string, len(lines))
+    for i, line := range lines {
+        n := fmt.Sprintf("%*d", digits, i+1)
+        switch {
+        case opts.Superscript:
+            n = mapDigits(n, superscriptDigits)
+        case opts.Subscript:
+            n = mapDigits(n, subscriptDigits)
+        }
+        numbered[i] = n + " " + line
+    }
+    return numbered
+}
+
+// mapDigits replaces the ASCII digits in s with the runes of set.
+func mapDigits(s string, set []rune) string {
+    return strings.Map(func(r rune) rune {
+        if r >= '0' && r <= '9' {
+            return set[r-'0']
+        }
+        return r
+    }, s)
+}
+
+// reverseLines returns lines in reverse order.
+func reverseLines(lines []string) []string {
+    reversed := make([]string, len(lines))
+    for i, line := range lines {
+        reversed[len(lines)-1-i] = line
+    }
+    return reversed
+}
+
+// limitLines keeps the first n lines and replaces the others with a row
+// counting them.
+func limitLines(lines []string, n int) []string {
+    if len(lines) <= n {
+        return lines
+    }
+    return append(lines[:n:n], fmt.Sprintf("(%d more)", len(lines)-n))
+}
+
+// collapsedSummary is the only row of a collapsed box.
+func collapsedSummary(title string, n int) string {
+    if title == "" {
+        return fmt.Sprintf("▸ %d lines", n)
+    }
+    return fmt.Sprintf("▸ %s (%d lines)", title, n)
+}
+
+// verticalLines puts every character on its own line, leaving an empty line
+// between the input lines. Escape sequences stay with the character after
+// them.
+func verticalLines(lines []string) []string {
+    var rotated []string
+    for i, line := range lines {
+        if i > 0 {
+            rotated = append(rotated, "")
+        }
+        var pending string
+        for _, c := range cells(line) {
+            if pending += c.s; c.width > 0 {
+                rotated = append(rotated, pending)
+                pending = ""
+            }
+        }
+    }
+    return rotated
+}
+
+// truncateLines cuts lines wider than width.
+func truncateLines(lines []string, width int) []string {
+    width = max(width, 0)
+    cut := make([]string, len(lines))
+    for i, line := range lines {
+        cut[i] = line
+        if visualLength(line) > width {
+            cut[i] = truncate(line, width)
+        }
+    }
+    return cut
+}
+
+// fitHeight cuts or pads lines to the rows of a box height rows tall.
+func fitHeight(lines []string, height int) []string {
+    rows := max(height-2, 0)
+    if len(lines) > rows {
+        return lines[:rows]
+    }
+    for len(lines) < rows {
+        lines = append(lines, "")
+    }
+    return lines
+}
+
+// reflowWidth returns the narrowest wrap width at which the lines fit into
+// the rows of a box of opts.Width and opts.Height.
+func reflowWidth(lines []string, style BoxStyle, opts Options) (int, error) {
+    if opts.Width <= 0 || opts.Height <= 0 {
+        return 0, errors.New("reflow needs a width and a height")
+    }
+    avail := opts.Width - 2*visualLength(style.Vertical) - 2
+    rows := opts.Height - 2
+    var needed int
+    // Narrower wraps leave no room beside the continuation indent.
+    for wrap := max(opts.WrapIndent, 0) + 1; wrap <= avail; wrap++ {
+        opts.Wrap, opts.WrapMode = wrap, Soft
+        needed = len(wrapLines(lines, opts))
+        if needed <= rows {
+            return wrap, nil
+        }
+    }
+    return 0, fmt.Errorf("reflow: content needs %d rows at width %d, the box has %d", needed, opts.Width, rows)
+}
+
+// boxLayout is a box sized for its lines, ready to be drawn.
+type boxLayout struct {
+    lines       []string
+    style       BoxStyle
+    // top is the style of the top border, which TitleStyle can set apart.
+    top         BoxStyle
+    titleDecor  string
+    titleAlign  Alignment
+    // titleTab draws the title in a tab above the top border.
+    titleTab    bool
+    // titleRows continue a wrapped title below the top border.
+    titleRows   []string
+    footerDecor string
+    footerAlign Alignment
+    // divider is the number of rows above the divider, 0 for none.
+    divider     int
+    // crosses are the columns of the content rows where the divider
+    // crosses a column line of a table.
+    crosses     []int
+    // sections are headings drawn as labeled dividers between rows.
+    sections    []section
+    innerWidth  int
+    borderColor ANSIColor
+    titleColor  ANSIColor
+    textColor   ANSIColor
+    highlights  []HighlightRule
+    shadow      ShadowStyle
+    align       Alignment
+    overlay     bool
+    // overflow describes the first line or row cut to fit, nil if none.
+    overflow error
+}
+
+// hasDivider reports whether the box of n rows has a divider.
+func (l boxLayout) hasDivider(n int) bool {
+    return l.divider > 0 && l.divider < n
+}
+
+// shadowGlyph draws the shadow of a box.
+const shadowGlyph = "░"
+
+// width returns the width of the box without its shadow.
+func (l boxLayout) width() int {
+    return l.innerWidth + 2*visualLength(l.style.Vertical)
+}
+
+// shadowCell returns the drop shadow drawn right of a row, if any.
+func (l boxLayout) shadowCell() string {
+    if l.shadow != DropShadow {
+        return ""
+    }
+    return shadowGlyph
+}
+
+// border returns the border glyphs s in the border color.
+func (l boxLayout) border(s string) string {
+    return Colorize(s, l.borderColor)
+}
+
+// drawBox writes lines framed with style to w.
+func drawBox(w io.Writer, lines []string, style BoxStyle, opts Options) {
+    layoutBox(lines, style, opts).draw(w)
+}
+
+// layoutBox applies the content transformations of opts to lines and sizes
+// the box framing them with style.
+func layoutBox(lines []string, style BoxStyle, opts Options) boxLayout {
+    if opts.Center {
+        opts.TextAlign = Center
+    }
+    if opts.Reverse {
+        lines = reverseLines(lines)
+    }
+    if opts.Collapse && opts.MaxLines > 0 && len(lines) > opts.MaxLines {
+        lines, opts.Title = []string{collapsedSummary(opts.Title, len(lines))}, ""
+    } else if opts.MaxLines > 0 {
+        lines = limitLines(lines, opts.MaxLines)
+    }
+    // Headings are taken out before the lines between them are wrapped or
+    // drawn as trees; an inner border or vertical text has no room for them.
+    var sections []section
+    if opts.SectionMarker != "" && !opts.InnerBorder && !opts.Vertical {
+        lines, sections = splitSections(lines, opts.SectionMarker)
+    }
+    lines, sections = eachSection(lines, sections, func(lines []string) []string {
+        if opts.Tree {
+            return treeLines(lines, style, opts)
+        }
+        return wrapLines(lines, opts)
+    })
+    if opts.LineNumbers {
+        lines = numberLines(lines, opts)
+    }
+    if opts.InnerBorder {
+        lines = innerBorderLines(lines, style, opts)
+        // The inner box draws the divider.
+        opts.TextAlign, opts.TitleSep = Left, false
+    }
+    theme, _ := LookupTheme(opts.Theme)
+    if opts.NoColor {
+        theme.BorderColor, theme.TitleColor, theme.ContentColor, theme.HighlightRules = nil, nil, nil, nil
+        opts.BorderColor, opts.TitleColor, opts.TitleUnderline, opts.HighlightRules = "", "", false, nil
+    }
+    titleColor := colorOr(parseColor(opts.TitleColor), theme.TitleColor)
+    title := opts.Title
+    if title != "" {
+        title = Colorize(title, titleColor)
+    }
+    if opts.TitleUnderline && title != "" {
+        title = sgrUnderline + title + sgrNoUnderline
+    }
+    if opts.FillBlock {
+        style.Horizontal = blockFill
+    }
+    // The top style is checked by prepare.
+    top, _ := opts.topStyle(style)
+    if opts.ASCII {
+        style, top = asciiStyle(style), asciiStyle(top)
+    }
+
+    minPadding := 2
+    if opts.Vertical {
+        lines = verticalLines(lines)
+        minPadding, opts.TextAlign = 0, Center
+    }
+    dividerAfter := 0
+    if opts.TitleSep && opts.HeaderLines > 0 {
+        dividerAfter = opts.HeaderLines
+    }
+    var overflow error
+    shadow := opts.Shadow
+    if shadow == NoShadow && theme.Shadow {
+        shadow = DropShadow
+    }
+    borderColor := colorOr(parseColor(opts.BorderColor), theme.BorderColor)
+    glyphWidth := visualLength(style.Horizontal)
+    // framed is the inner width that fits decor in a border with a fill
+    // glyph between it and each corner.
+    framed := func(decor string) int {
+        return visualLength(decor) + 2*glyphWidth
+    }
+    titleCaps := func(title string) string {
+        return Colorize(top.TitleLeft, borderColor) + " " + title + " " + Colorize(top.TitleRight, borderColor)
+    }
+    // With TitleWrap, a title too wide for the fixed width continues on
+    // further rows of the top border.
+    var titleRows []string
+    if opts.TitleWrap && opts.Width > 0 && title != "" && !opts.TitleTab {
+        avail := opts.Width - 2*visualLength(style.Vertical) - framed(titleCaps(""))
+        if segments := Wrap(title, avail, Soft); avail > 0 && len(segments) > 1 {
+            for _, segment := range segments {
+                titleRows = append(titleRows, titleCaps(segment))
+            }
+        }
+    }
+    if opts.Height > 0 {
+        // The divider and an inner shadow take a row of the height.
+        height := opts.Height - min(dividerAfter, 1)
+        if shadow == InnerShadow {
+            height--
+        }
+        if opts.TitleTab && title != "" {
+            height -= tabRows
+        }
+        height -= max(len(titleRows)-1, 0)
+        height -= len(sections)
+        if opts.Progress >= 0 {
+            height--
+        }
+        if rows := max(height-2, 0); len(lines) > rows {
+            overflow = fmt.Errorf("%w: %d lines, the box has %d rows", ErrOverflow, len(lines), rows)
+        }
+        lines = fitHeight(lines, height)
+        for i, s := range sections {
+            if s.at > len(lines) {
+                sections = sections[:i]
+                break
+            }
+        }
+    }
+    fixedWidth := 0
+    if opts.Width > 0 {
+        fixedWidth = max(opts.Width-2*visualLength(style.Vertical), 0)
+        for i, line := range lines {
+            if w := visualLength(line); w > fixedWidth-minPadding && overflow == nil {
+                overflow = fmt.Errorf("%w: line %d is %d columns wide, the box fits %d", ErrOverflow, i+1, w, max(fixedWidth-minPadding, 0))
+            }
+        }
+        lines = truncateLines(lines, fixedWidth-minPadding)
+    }
+
+    // Calculate maximum content width.
+    maxContentWidth := 0
+    if opts.Progress >= 0 {
+        maxContentWidth = progressMinWidth + visualLength(opts.progressLabel())
+    }
+    for _, line := range lines {
+        if l := visualLength(line); l > maxContentWidth {
+            maxContentWidth = l
+        }
+    }
+
+    for i, line := range lines {
+        debugf(opts.Debug, "line %d: width %d", i+1, visualLength(line))
+    }
+    debugf(opts.Debug, "maxContentWidth: %d", maxContentWidth)
+
+    innerWidth := max(maxContentWidth+minPadding, max(opts.TitleMinBody, fixedWidth))
+
+    // The footer widens the box before the width is fitted to the glyphs,
+    // so that the top border has the same width.
+    var footerDecor string
+    if opts.Footer != "" {
+        footerDecor = " " + opts.Footer + " "
+        innerWidth = max(innerWidth, framed(footerDecor))
+    }
+
+    headingWidth := 0
+    if fixedWidth > 0 {
+        headingWidth = max(fixedWidth-2*glyphWidth, 1)
+    }
+    for i, s := range sections {
+        sections[i].decor = headingDecor(s.decor, style, headingWidth, borderColor, titleColor)
+        innerWidth = max(innerWidth, framed(sections[i].decor))
+    }
+
+    // Handle title decoration.
+    var titleDecor string
+    if opts.TitleFit == TitleClip && len(titleRows) == 0 && title != "" {
+        room := innerWidth - framed(titleCaps(""))
+        if opts.TitleTab {
+            room = innerWidth - 2
+        }
+        title = clipTitle(title, room)
+    }
+    if len(titleRows) > 0 {
+        // Columns the glyphs cannot fill widen each title row.
+        for _, decor := range titleRows {
+            innerWidth = max(innerWidth, framed(decor))
+        }
+        titleDecor, titleRows = titleRows[0], titleRows[1:]
+        innerWidth = fitWidth(innerWidth, 0, glyphWidth)
+    } else if title != "" && opts.TitleTab {
+        // The tab frames the title with the sides of the box instead of
+        // the title caps, and must fit above the top border.
+        titleDecor = " " + title + " "
+        innerWidth = fitWidth(max(innerWidth, visualLength(titleDecor)), 0, glyphWidth)
+    } else if title != "" {
+        titleDecor = titleCaps(title)
+        innerWidth = fitWidth(max(innerWidth, framed(titleDecor)), visualLength(titleDecor), glyphWidth)
+    } else {
+        innerWidth = fitWidth(innerWidth, 0, glyphWidth)
+    }
+
+    if opts.TextAlign == Justify {
+        lines = AlignLines(lines, innerWidth-minPadding, Justify)
+    }
+    if opts.Progress >= 0 {
+        lines = append(lines, progressBar(innerWidth-minPadding, opts))
+    }
+
+    debugf(opts.Debug, "innerWidth: %d", innerWidth)
+    return boxLayout{
+        lines:       lines,
+        style:       style,
+        top:         top,
+        titleDecor:  titleDecor,
+        titleAlign:  opts.TitleAlign,
+        titleTab:    opts.TitleTab && titleDecor != "",
+        titleRows:   titleRows,
+        footerDecor: footerDecor,
+        footerAlign: opts.FooterAlign,
+        divider:     dividerAfter,
+        sections:    sections,
+        innerWidth:  innerWidth,
+        borderColor: borderColor,
+        titleColor:  titleColor,
+        textColor:   theme.ContentColor,
+        highlights:  append(append([]HighlightRule(nil), opts.HighlightRules...), theme.HighlightRules...),
+        shadow:      shadow,
+        align:       opts.TextAlign,
+        overlay:     opts.Overlay,
+        overflow:    overflow,
+    }
+}
+
+// draw writes the box to w.
+func (l boxLayout) draw(w io.Writer) error {
+    if err := l.drawTop(w); err != nil {
+        return err
+    }
+    for i, line := range l.lines {
+        if err := l.drawHeadings(w, i); err != nil {
+            return err
+        }
+        if err := l.drawRow(w, line); err != nil {
+            return err
+        }
+        if i+1 == l.divider && l.hasDivider(len(l.lines)) {
+            if err := l.drawDivider(w); err != nil {
+                return err
+            }
+        }
+    }
+    if err := l.drawHeadings(w, len(l.lines)); err != nil {
+        return err
+    }
+    return l.drawBottom(w)
+}
+
+// drawDivider writes a divider across the interior, joined to the border
+// and, with one column wide glyphs, to the column lines at l.crosses.
+func (l boxLayout) drawDivider(w io.Writer) error {
+    left, right := teeGlyphs(l.style)
+    fill := repeatChar(l.style.Horizontal, l.innerWidth/visualLength(l.style.Horizontal))
+    if cross := crossGlyph(l.style); len(l.crosses) > 0 && visualLength(l.style.Horizontal) == 1 && visualLength(cross) == 1 {
+        glyphs := make([]string, l.innerWidth)
+        for i := range glyphs {
+            glyphs[i] = l.style.Horizontal
+        }
+        offset := l.leftPad(l.lines[0])
+        for _, c := range l.crosses {
+            if i := offset + c; i < len(glyphs) {
+                glyphs[i] = cross
+            }
+        }
+        fill = strings.Join(glyphs, "")
+    }
+    _, err := fmt.Fprintf(w, "%s%s\n", l.border(left+fill+right), l.shadowCell())
+    return err
+}
+
+// drawTop writes the top border with the title, followed by the row of an
+// inner shadow.
+func (l boxLayout) drawTop(w io.Writer) error {
+    if err := l.drawTopBorder(w); err != nil || l.shadow != InnerShadow {
+        return err
+    }
+    _, err := fmt.Fprintf(w, "%s%s%s%s\n",
+        l.border(l.style.Vertical),
+        repeatChar(shadowGlyph, l.innerWidth/visualLength(shadowGlyph)),
+        blank(l.innerWidth%visualLength(shadowGlyph), l.overlay),
+        l.border(l.style.Vertical))
+    return err
+}
+
+// drawTopBorder writes the top border with the title in the top style.
+func (l boxLayout) drawTopBorder(w io.Writer) error {
+    l.style = l.top
+    style, innerWidth := l.style, l.innerWidth
+    glyphWidth := visualLength(style.Horizontal)
+    // The drop shadow starts one row below the top border.
+    var gap string
+    if l.shadow == DropShadow {
+        gap = blank(visualLength(shadowGlyph), l.overlay)
+    }
+    if l.titleTab {
+        return l.drawTab(w, gap)
+    }
+    if l.titleDecor == "" {
+        _, err := fmt.Fprintf(w, "%s%s\n",
+            l.border(style.TopLeft+repeatChar(style.Horizontal, innerWidth/glyphWidth)+style.TopRight),
+            gap)
+        return err
+    }
+    if len(l.titleRows) > 0 {
+        if err := l.drawTitleRow(w, style.TopLeft, style.TopRight, l.titleDecor, gap); err != nil {
+            return err
+        }
+        left, right := teeGlyphs(style)
+        for _, decor := range l.titleRows {
+            if err := l.drawTitleRow(w, left, right, decor, l.shadowCell()); err != nil {
+                return err
+            }
+        }
+        return nil
+    }
+    return l.drawTitleRow(w, style.TopLeft, style.TopRight, l.titleDecor, gap)
+}
+
+// drawTitleRow writes a row of a (wrapped) title between the glyphs left and
+// right, widening decor by the columns the horizontal glyphs cannot fill.
+func (l boxLayout) drawTitleRow(w io.Writer, left, right, decor, after string) error {
+    glyphWidth := visualLength(l.style.Horizontal)
+    remaining := l.innerWidth - visualLength(decor)
+    leftFill, rightFill := borderFill(remaining/glyphWidth, l.titleAlign)
+    _, err := fmt.Fprintf(w, "%s%s%s%s%s\n",
+        l.border(left+repeatChar(l.style.Horizontal, leftFill)),
+        decor,
+        strings.Repeat(" ", remaining%glyphWidth),
+        l.border(repeatChar(l.style.Horizontal, rightFill)+right),
+        after)
+    return err
+}
+
+// leftPad returns the number of columns before line in its content row.
+func (l boxLayout) leftPad(line string) int {
+    pad := l.innerWidth - visualLength(line)
+    switch l.align {
+    case Center:
+        return pad / 2
+    case Right:
+        return max(pad-1, 0)
+    }
+    return 1
+}
+
+// drawRow writes one content row.
+func (l boxLayout) drawRow(w io.Writer, line string) error {
+    leftPad := l.leftPad(line)
+    rightPad := max(l.innerWidth-visualLength(line)-leftPad, 0)
+    if line != "" {
+        line = Colorize(line, highlight(l.highlights, line, l.textColor))
+    }
+    left := blank(leftPad, l.overlay)
+    if n := visualLength(shadowGlyph); l.shadow == InnerShadow && leftPad >= n {
+        left = shadowGlyph + blank(leftPad-n, l.overlay)
+    }
+    _, err := fmt.Fprintf(w, "%s%s%s%s%s%s\n",
+        l.border(l.style.Vertical),
+        left,
+        line,
+        blank(rightPad, l.overlay),
+        l.border(l.style.Vertical),
+        l.shadowCell())
+    return err
+}
+
+// alignFill splits n fill glyphs into those left and right of a title
+// aligned left, center or right.
+func alignFill(n int, align Alignment) (left, right int) {
+    switch align {
+    case Left:
+        return 0, n
+    case Right:
+        return n, 0
+    }
+    return n / 2, n - n/2
+}
+
+// borderFill is alignFill for text embedded in a border, keeping a fill
+// glyph between the text and each corner.
+func borderFill(n int, align Alignment) (left, right int) {
+    if n < 2 {
+        return alignFill(n, align)
+    }
+    left, right = alignFill(n-2, align)
+    return left + 1, right + 1
+}
+
+// drawBottom writes the bottom border with the footer and the shadow below
+// it.
+func (l boxLayout) drawBottom(w io.Writer) error {
+    style := l.style
+    glyphWidth := visualLength(style.Horizontal)
+    bottom := l.border(style.BottomLeft + repeatChar(style.Horizontal, l.innerWidth/glyphWidth) + style.BottomRight)
+    if l.footerDecor != "" {
+        // Columns the glyphs cannot fill widen the footer.
+        remaining := l.innerWidth - visualLength(l.footerDecor)
+        footer := l.footerDecor + strings.Repeat(" ", remaining%glyphWidth)
+        leftFill, rightFill := borderFill(remaining/glyphWidth, l.footerAlign)
+        bottom = l.border(style.BottomLeft+repeatChar(style.Horizontal, leftFill)) +
+            footer +
+            l.border(repeatChar(style.Horizontal, rightFill)+style.BottomRight)
+    }
+    _, err := fmt.Fprintf(w, "%s%s\n", bottom, l.shadowCell())
+    if err != nil || l.shadow != DropShadow {
+        return err
+    }
+    _, err = fmt.Fprintf(w, "%s%s\n", blank(visualLength(shadowGlyph), l.overlay), repeatChar(shadowGlyph, l.width()/visualLength(shadowGlyph)))
+    return err
+}
@@ -0,0 +1,544 @@
+package textbox
+
+import (
+    "bytes"
+    "errors"
+    "io"
+    "math"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestRenderEmptyInputWideGlyph(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Style, opts.Char = CustomStyle, "中"
+    tests := []struct {
+        lines []string
+        top   string
+    }{
+        {nil, "中中中"},
+        {[]string{"a"}, "中中中中"},
+    }
+    for _, tt := range tests {
+        var buf bytes.Buffer
+        if err := Render(&buf, tt.lines, opts); err != nil {
+            t.Fatal(err)
+        }
+
+        rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        if rows[0] != tt.top {
+            t.Errorf("lines %q: top border %q, want %q", tt.lines, rows[0], tt.top)
+        }
+        for _, row := range rows {
+            if visualLength(row) != visualLength(rows[0]) {
+                t.Errorf("lines %q: row %q is %d wide, want %d", tt.lines, row, visualLength(row), visualLength(rows[0]))
+            }
+        }
+    }
+}
+
+func TestRenderTitleAndFooterAlignment(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Title, opts.Footer, opts.Width = "T", "F", 14
+    tests := []struct {
+        titleAlign, footerAlign Alignment
+        top, bottom             string
+    }{
+        {Center, Right, "┌───┘ T └────┐", "└──────── F ─┘"},
+        {Left, Center, "┌─┘ T └──────┐", "└──── F ─────┘"},
+        {Right, Left, "┌──────┘ T └─┐", "└─ F ────────┘"},
+    }
+    for _, tt := range tests {
+        opts.TitleAlign, opts.FooterAlign = tt.titleAlign, tt.footerAlign
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"body"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        if top := rows[0]; top != tt.top {
+            t.Errorf("title %v: top border %q, want %q", tt.titleAlign, top, tt.top)
+        }
+        if bottom := rows[len(rows)-1]; bottom != tt.bottom {
+            t.Errorf("footer %v: bottom border %q, want %q", tt.footerAlign, bottom, tt.bottom)
+        }
+    }
+}
+
+func TestRenderTitleCornerGap(t *testing.T) {
+    tests := []struct {
+        name          string
+        style         string
+        title, footer string
+        align         Alignment
+        lines         []string
+        want          string
+    }{
+        {"title one column wider", "2", "T", "", Center, []string{"abcd"},
+            "╭─╯ T ╰─╮\n│ abcd  │\n╰───────╯\n"},
+        {"title exactly fitting", "2", "T", "", Center, []string{"abcde"},
+            "╭─╯ T ╰─╮\n│ abcde │\n╰───────╯\n"},
+        {"title left", "2", "T", "", Left, []string{"abcdefg"},
+            "╭─╯ T ╰───╮\n│ abcdefg │\n╰─────────╯\n"},
+        {"title right", "3", "T", "", Right, []string{"abcdefg"},
+            "╔═══╝ T ╚═╗\n║ abcdefg ║\n╚═════════╝\n"},
+        {"footer", "3", "", "Foot", Left, []string{"ab"},
+            "╔════════╗\n║ ab     ║\n╚═ Foot ═╝\n"},
+        {"section heading", "3", "", "", Center, []string{"ab", "## Head", "cd"},
+            "╔══════════╗\n║ ab       ║\n╠═╝ Head ╚═╣\n║ cd       ║\n╚══════════╝\n"},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Style, opts.Title, opts.Footer, opts.SectionMarker = tt.style, tt.title, tt.footer, "##"
+        opts.TitleAlign, opts.FooterAlign = tt.align, tt.align
+        var buf bytes.Buffer
+        if err := Render(&buf, tt.lines, opts); err != nil {
+            t.Fatal(err)
+        }
+        if buf.String() != tt.want {
+            t.Errorf("%s:\n%s\nwant:\n%s", tt.name, buf.String(), tt.want)
+        }
+    }
+}
+
+func TestRenderTitleStyle(t *testing.T) {
+    tests := []struct {
+        style, titleStyle string
+        want              string
+    }{
+        {"1", "3", "╒═╝ T ╚═╕\n│ body  │\n└───────┘\n"},
+        {"3", "1", "╓─┘ T └─╖\n║ body  ║\n╚═══════╝\n"},
+        // Styles with the same sides keep the corners of the title style.
+        {"1", "2", "╭─╯ T ╰─╮\n│ body  │\n└───────┘\n"},
+        {"1", "", "┌─┘ T └─┐\n│ body  │\n└───────┘\n"},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Style, opts.TitleStyle, opts.Title = tt.style, tt.titleStyle, "T"
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"body"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        if buf.String() != tt.want {
+            t.Errorf("style %s with title style %q:\n%s\nwant:\n%s", tt.style, tt.titleStyle, buf.String(), tt.want)
+        }
+    }
+
+    opts := DefaultOptions()
+    opts.TitleStyle = "nope"
+    if err := Render(io.Discard, []string{"x"}, opts); !errors.Is(err, ErrUnknownStyle) {
+        t.Errorf("unknown title style: %v, want ErrUnknownStyle", err)
+    }
+    opts.Style, opts.Char, opts.TitleStyle = CustomStyle, "中", "1"
+    if err := Render(io.Discard, []string{"x"}, opts); err == nil {
+        t.Error("title style narrower than the style succeeded, want an error")
+    }
+}
+
+func TestVisualLengthGraphemeClusters(t *testing.T) {
+    tests := []struct {
+        name string
+        s    string
+        want int
+    }{
+        {"zwj family", "👨‍👩‍👧‍👦", 2},
+        {"rainbow flag", "🏳️‍🌈", 2},
+        {"country flag", "🇩🇪", 2},
+        {"two flags", "🇩🇪🇫🇷", 4},
+        {"keycap", "1️⃣", 2},
+        {"skin tone", "👍🏽", 2},
+        {"text around", "a👨‍👩‍👧‍👦b", 4},
+    }
+    for _, tt := range tests {
+        if got := visualLength(tt.s); got != tt.want {
+            t.Errorf("%s: visualLength(%q) = %d, want %d", tt.name, tt.s, got, tt.want)
+        }
+    }
+}
+
+func TestRenderGraphemeClustersAligned(t *testing.T) {
+    lines := []string{"family 👨‍👩‍👧‍👦", "flag 🏳️‍🌈", "de 🇩🇪", "key 1️⃣", "ok 👍🏽", "plain"}
+    var buf bytes.Buffer
+    if err := Render(&buf, lines, DefaultOptions()); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    for _, row := range rows {
+        if visualLength(row) != visualLength(rows[0]) {
+            t.Errorf("row %q is %d wide, want %d", row, visualLength(row), visualLength(rows[0]))
+        }
+    }
+    if want := len(lines) + 2; len(rows) != want {
+        t.Errorf("got %d rows, want %d", len(rows), want)
+    }
+}
+
+func TestWrapKeepsGraphemeClusters(t *testing.T) {
+    family := "👨‍👩‍👧‍👦"
+    got := Wrap(family+family+family, 4, Hard)
+    want := []string{family + family, family}
+    if strings.Join(got, "|") != strings.Join(want, "|") {
+        t.Errorf("Wrap = %q, want %q", got, want)
+    }
+}
+
+func TestVisualLengthCombiningCharacters(t *testing.T) {
+    tests := []struct {
+        name string
+        s    string
+        want int
+    }{
+        {"nfd latin", "cafe\u0301", 4},
+        {"nfd latin stacked", "a\u0308\u0301bc", 3},
+        {"hangul jamo", "\u1100\u1161\u11a8", 2},
+        {"hangul jamo word", "\u1112\u1161\u11ab\u1100\u1173\u11af", 4},
+        {"thai vowels", "\u0e01\u0e34\u0e19", 2},
+        {"thai tone mark", "\u0e19\u0e49\u0e33", 2},
+    }
+    for _, tt := range tests {
+        if got := visualLength(tt.s); got != tt.want {
+            t.Errorf("%s: visualLength(%q) = %d, want %d", tt.name, tt.s, got, tt.want)
+        }
+    }
+}
+
+func TestRenderCombiningCharactersAligned(t *testing.T) {
+    lines := []string{"cafe\u0301", "\u1100\u1161\u11a8", "\u0e01\u0e34\u0e19", "plain"}
+    opts := DefaultOptions()
+    opts.Wrap = 3
+    var buf bytes.Buffer
+    if err := Render(&buf, lines, opts); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    for _, row := range rows {
+        if visualLength(row) != visualLength(rows[0]) {
+            t.Errorf("row %q is %d wide, want %d", row, visualLength(row), visualLength(rows[0]))
+        }
+    }
+    for _, row := range rows {
+        if strings.HasPrefix(strings.TrimPrefix(row, "\u2502 "), "\u0301") {
+            t.Errorf("row %q starts with a combining mark split from its base", row)
+        }
+    }
+}
+
+func TestWrapKeepsLeadingIndentation(t *testing.T) {
+    tests := []struct {
+        s    string
+        want []string
+    }{
+        {"    abcdefghij", []string{"    abcd", "efghij"}},
+        {"    ab cdefgh", []string{"    ab", "cdefgh"}},
+        {"  a b c d e", []string{"  a b c", "d e"}},
+    }
+    for _, tt := range tests {
+        got := Wrap(tt.s, 8, Soft)
+        if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+            t.Errorf("Wrap(%q, 8, Soft) = %q, want %q", tt.s, got, tt.want)
+        }
+    }
+}
+
+func TestWrapIndentNotWiderThanWrap(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Wrap, opts.WrapIndent = 4, 6
+    for _, mode := range []WrapMode{Soft, Hard} {
+        opts.WrapMode = mode
+        if err := Render(io.Discard, []string{"abcdefgh"}, opts); err == nil {
+            t.Errorf("%s: Render with wrap indent 6 > wrap 4 succeeded, want an error", mode)
+        }
+    }
+    // The wrappers end even when the indentation leaves no room.
+    for _, pieces := range [][]string{
+        softWrap("abcdefgh", 4, "      ", false),
+        hardWrap("abcdefgh", 4, "      ", `\`),
+    } {
+        if len(pieces) > 8 {
+            t.Errorf("wrapping 8 cells gave %d pieces: %q", len(pieces), pieces)
+        }
+    }
+}
+
+func TestPreserveIndentLeavesRoom(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Wrap, opts.PreserveIndent = 8, true
+    tests := []struct {
+        line string
+        want []string
+    }{
+        {"  abc def ghi", []string{"  abc", "  def", "  ghi"}},
+        {"        abcdefghij", []string{"abcdefgh", "ij"}},
+        {"\t\tab cd ef gh", []string{"\t\tab cd ef", "\t\tgh"}},
+        {"\t\t\t\t\t\t\t\tabcdefghij", []string{"\t\t\t\t\t\t\t\tabcdefgh", "ij"}},
+    }
+    for _, tt := range tests {
+        got := wrapLines([]string{tt.line}, opts)
+        if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+            t.Errorf("wrapLines(%q) = %q, want %q", tt.line, got, tt.want)
+        }
+    }
+}
+
+func TestRenderTitleWideGlyph(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Style, opts.Char = CustomStyle, "＊"
+    for _, tt := range []struct{ title, footer string }{
+        {"T", ""}, {"Ti", ""}, {"T", "ab"}, {"Ti", "abc"},
+    } {
+        opts.Title, opts.Footer = tt.title, tt.footer
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"a"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        for _, row := range rows {
+            if visualLength(row) != visualLength(rows[0]) {
+                t.Errorf("title %q, footer %q: row %q is %d wide, want %d", tt.title, tt.footer, row, visualLength(row), visualLength(rows[0]))
+            }
+        }
+    }
+}
+
+func TestRenderFooterWideGlyph(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Style, opts.Char = CustomStyle, "中"
+    for _, footer := range []string{"xyz12", "xyz123", "a"} {
+        opts.Footer = footer
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"a"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        for _, row := range rows {
+            if visualLength(row) != visualLength(rows[0]) {
+                t.Errorf("footer %q: row %q is %d wide, want %d", footer, row, visualLength(row), visualLength(rows[0]))
+            }
+        }
+    }
+}
+
+func TestRenderTitleTab(t *testing.T) {
+    tests := []struct {
+        align Alignment
+        want  string
+    }{
+        {Left, `
+┌───────┐
+│ Notes │
+├───────┴─────┐
+│ hello world │
+└─────────────┘
+`},
+        {Right, `
+      ┌───────┐
+      │ Notes │
+┌─────┴───────┤
+│ hello world │
+└─────────────┘
+`},
+        {Center, `
+   ┌───────┐
+   │ Notes │
+┌──┴───────┴──┐
+│ hello world │
+└─────────────┘
+`},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Title, opts.TitleTab, opts.TitleAlign = "Notes", true, tt.align
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"hello world"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        if want := strings.TrimPrefix(tt.want, "\n"); buf.String() != want {
+            t.Errorf("title tab %v:\n%s\nwant:\n%s", tt.align, buf.String(), want)
+        }
+        d, err := Measure([]string{"hello world"}, WithOptions(opts))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if rows := strings.Count(buf.String(), "\n"); d.Height != rows {
+            t.Errorf("title tab %v: Measure height %d, Render wrote %d rows", tt.align, d.Height, rows)
+        }
+    }
+}
+
+func TestRenderProgress(t *testing.T) {
+    tests := []struct {
+        progress float64
+        label    bool
+        want     string
+    }{
+        {0, false, "│ ░░░░░░░░░░░ │"},
+        {0.5, false, "│ ██████░░░░░ │"},
+        {1, true, "│ ██████████ 100% │"},
+        {0.42, true, "│ ████░░░░░░  42% │"},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Progress, opts.ProgressLabel = tt.progress, tt.label
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"downloading"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        if len(rows) != 4 || rows[2] != tt.want {
+            t.Errorf("progress %v:\n%s\nwant the bar row %q", tt.progress, buf.String(), tt.want)
+        }
+    }
+
+    opts := DefaultOptions()
+    opts.Progress, opts.Height = 0.5, 4
+    d, err := Measure([]string{"a", "b", "c"}, WithOptions(opts))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if d.Height != 4 || d.Rows[2] != RowContent {
+        t.Errorf("progress with height 4: %d rows %v, want the bar to take a row of the height", d.Height, d.Rows)
+    }
+    for _, p := range []float64{1.5, math.NaN()} {
+        opts.Progress = p
+        if err := Render(io.Discard, nil, opts); err == nil {
+            t.Errorf("Render with progress %v succeeded, want an error", p)
+        }
+    }
+}
+
+func TestRenderTitleWrap(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Title, opts.TitleWrap, opts.Width = "A rather long title that needs wrapping", true, 22
+    var buf bytes.Buffer
+    if err := Render(&buf, []string{"content"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := `┌─┘ A rather long └──┐
+├───┘ title that └───┤
+├─┘ needs wrapping └─┤
+│ content            │
+└────────────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("wrapped title:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    opts.Height = 6
+    d, err := Measure([]string{"a", "b", "c"}, WithOptions(opts))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if d.Height != 6 || d.Width != 22 {
+        t.Errorf("wrapped title in a 22x6 box: %dx%d", d.Width, d.Height)
+    }
+
+    // Without a fixed width the title widens the box as before.
+    opts.Width, opts.Height = 0, 0
+    buf.Reset()
+    if err := Render(&buf, []string{"content"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    if rows := strings.Count(buf.String(), "\n"); rows != 3 {
+        t.Errorf("title-wrap without width drew %d rows:\n%s", rows, buf.String())
+    }
+}
+
+func TestRenderSections(t *testing.T) {
+    opts := DefaultOptions()
+    opts.SectionMarker, opts.TitleAlign, opts.Wrap = "##", Left, 10
+    lines := []string{"intro", "## Install", "run make install", "##", "end"}
+    var buf bytes.Buffer
+    if err := Render(&buf, lines, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := `┌─────────────┐
+│ intro       │
+├─┘ Install └─┤
+│ run make    │
+│ install     │
+├─────────────┤
+│ end         │
+└─────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("sections:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    // Headings take rows of a fixed height.
+    d, err := Measure(lines, WithOptions(opts), WithHeight(9))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want2 := []RowKind{RowBorder, RowContent, RowDivider, RowContent, RowContent, RowDivider, RowContent, RowContent, RowBorder}
+    if !reflect.DeepEqual(d.Rows, want2) {
+        t.Errorf("rows of a box of height 9 = %v, want %v", d.Rows, want2)
+    }
+
+    // A marker matching no line draws every line as content.
+    opts.SectionMarker = "=="
+    buf.Reset()
+    if err := Render(&buf, lines, opts); err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(buf.String(), "## Install") {
+        t.Errorf("headings drawn with another marker:\n%s", buf.String())
+    }
+}
+
+func TestRenderTitleFit(t *testing.T) {
+    tests := []struct {
+        title string
+        fit   TitleFit
+        want  string
+    }{
+        {"Long title", TitleGrow, "┌─┘ Long title └─┐"},
+        {"Long title", TitleClip, "┌─┘ Lo… └─┐"},
+        {"Tiny", TitleClip, "┌─┘ Ti… └─┐"},
+        {"Tin", TitleClip, "┌─┘ Tin └─┐"},
+        {"Dropped", TitleClip, "┌───┐"},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Title, opts.TitleFit = tt.title, tt.fit
+        lines := []string{"content"}
+        if tt.title == "Dropped" {
+            lines = []string{"x"}
+        }
+        var buf bytes.Buffer
+        if err := Render(&buf, lines, opts); err != nil {
+            t.Fatal(err)
+        }
+        if top, _, _ := strings.Cut(buf.String(), "\n"); top != tt.want {
+            t.Errorf("title %q with %v: top border %q, want %q", tt.title, tt.fit, top, tt.want)
+        }
+    }
+    for _, name := range []string{"grow", "CLIP"} {
+        if _, err := ParseTitleFit(name); err != nil {
+            t.Errorf("ParseTitleFit(%q): %v", name, err)
+        }
+    }
+    if _, err := ParseTitleFit("shrink"); err == nil {
+        t.Error("ParseTitleFit(\"shrink\") succeeded, want an error")
+    }
+}
+
+func TestRenderInnerBorderTitle(t *testing.T) {
+    opts := DefaultOptions()
+    opts.InnerBorder, opts.Title = true, "Title"
+    var buf bytes.Buffer
+    if err := Render(&buf, []string{"a"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    // The inner box widens to the title, one space inside the outer border.
+    want := `┌─┘ Title └─┐
+│           │
+│ ┌───────┐ │
+│ │ a     │ │
+│ └───────┘ │
+│           │
+└───────────┘
+`
+    if buf.String() != want {
+        t.Errorf("inner border:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
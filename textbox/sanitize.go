@@ -0,0 +1,212 @@
+package textbox
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+
+    "golang.org/x/text/unicode/norm"
+)
+
+// Policy selects what Sanitize keeps of untrusted text.
+type Policy int
+
+// Sanitize policies.
+const (
+    // PreserveSGR keeps the escape sequences setting colors and text
+    // attributes and removes all others.
+    PreserveSGR Policy = iota
+    // Strict removes every escape sequence.
+    Strict
+)
+
+// sgrSequence matches an escape sequence setting colors or text attributes.
+var sgrSequence = regexp.MustCompile(`^\x1b\[[0-9;:]*m$`)
+
+// Sanitize removes from line what could take over the terminal: C0 and C1
+// control characters other than tab, bare ESC bytes, invalid UTF-8 and
+// escape sequences including OSC, DCS and other string sequences up to
+// their terminator. PreserveSGR keeps color and attribute sequences.
+func Sanitize(line string, policy Policy) string {
+    var b strings.Builder
+    for i := 0; i < len(line); {
+        if line[i] == '\x1b' {
+            n := escapeLen(line[i:])
+            if seq := line[i : i+n]; policy == PreserveSGR && sgrSequence.MatchString(seq) {
+                b.WriteString(seq)
+            }
+            i += n
+            continue
+        }
+        r, size := utf8.DecodeRuneInString(line[i:])
+        if (r != utf8.RuneError || size > 1) && (r == '\t' || !unicode.IsControl(r)) {
+            b.WriteRune(r)
+        }
+        i += size
+    }
+    return b.String()
+}
+
+// escapeLen returns the length of the escape sequence s starts with. An
+// unterminated sequence extends to the end of s; a bare ESC has length 1.
+func escapeLen(s string) int {
+    if len(s) < 2 {
+        return len(s)
+    }
+    switch s[1] {
+    case '[':
+        for i := 2; i < len(s); i++ {
+            switch c := s[i]; {
+            case c >= 0x40 && c <= 0x7e:
+                return i + 1
+            case c < 0x20 || c > 0x7e:
+                return i
+            }
+        }
+        return len(s)
+    case ']', 'P', 'X', '^', '_':
+        for i := 2; i < len(s); i++ {
+            if s[i] == '\a' && s[1] == ']' {
+                return i + 1
+            }
+            if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+                return i + 2
+            }
+        }
+        return len(s)
+    }
+    if s[1] >= 0x20 && s[1] <= 0x7e {
+        return 2
+    }
+    return 1
+}
+
+// ParsePolicy returns the policy named strict or sgr.
+func ParsePolicy(name string) (Policy, error) {
+    switch name {
+    case "strict":
+        return Strict, nil
+    case "sgr":
+        return PreserveSGR, nil
+    }
+    return 0, fmt.Errorf("invalid sanitize policy %q, use strict, sgr or off", name)
+}
+
+// Replacements of control characters.
+const (
+    controlsKeep     = "keep"
+    controlsStrip    = "strip"
+    controlsCaret    = "caret"
+    controlsPictures = "pictures"
+)
+
+// replaceControls replaces the C0 and C1 control characters of line other
+// than tab and those of escape sequences according to mode: strip
+// removes them, caret writes ^G and pictures the control pictures such as
+// ␇. Keep or an empty mode leaves line alone.
+func replaceControls(line, mode string) string {
+    if mode == "" || mode == controlsKeep {
+        return line
+    }
+    var b strings.Builder
+    for i := 0; i < len(line); {
+        if line[i] == '\x1b' {
+            // Escape sequences keep their terminators, such as the BEL
+            // ending an OSC sequence.
+            n := escapeLen(line[i:])
+            b.WriteString(line[i : i+n])
+            i += n
+            continue
+        }
+        r, size := utf8.DecodeRuneInString(line[i:])
+        i += size
+        if r == '\t' || !unicode.IsControl(r) {
+            b.WriteString(line[i-size : i])
+            continue
+        }
+        switch mode {
+        case controlsCaret:
+            switch {
+            case r == 0x7f:
+                b.WriteString("^?")
+            case r < 0x20:
+                b.WriteString("^" + string(r+0x40))
+            default:
+                // C1 controls stand for ESC followed by the character
+                // 0x40 lower.
+                b.WriteString("^[" + string(r-0x40))
+            }
+        case controlsPictures:
+            switch {
+            case r == 0x7f:
+                b.WriteRune('\u2421')
+            case r < 0x20:
+                b.WriteRune(0x2400 + r)
+            default:
+                b.WriteRune(utf8.RuneError)
+            }
+        }
+    }
+    return b.String()
+}
+
+// sanitize applies the Normalize, ControlChars and Sanitize options to
+// lines, title and footer and expands their tabs.
+func (o *Options) sanitize(lines []string) []string {
+    clean := make([]string, len(lines))
+    for i, line := range lines {
+        clean[i] = expandTabs(o.sanitizeText(line))
+    }
+    o.Title, o.Footer = expandTabs(o.sanitizeText(o.Title)), expandTabs(o.sanitizeText(o.Footer))
+    return clean
+}
+
+// tabWidth is the distance between the tab stops of expandTabs.
+const tabWidth = 8
+
+// expandTabs replaces the tabs of s with spaces up to the next tab stop,
+// counting columns from the start of s, so that the border measures the
+// text as it is shown.
+func expandTabs(s string) string {
+    if !strings.Contains(s, "\t") {
+        return s
+    }
+    var b strings.Builder
+    col := 0
+    for i, part := range strings.Split(s, "\t") {
+        if i > 0 {
+            n := tabWidth - col%tabWidth
+            b.WriteString(strings.Repeat(" ", n))
+            col += n
+        }
+        b.WriteString(part)
+        col += visualLength(part)
+    }
+    return b.String()
+}
+
+// Unicode normalization forms.
+const (
+    normalizeNone = "none"
+    normalizeNFC  = "nfc"
+    normalizeNFKC = "nfkc"
+)
+
+// sanitizeText applies the Normalize, ControlChars and Sanitize options to
+// s.
+func (o *Options) sanitizeText(s string) string {
+    switch o.Normalize {
+    case normalizeNFC:
+        s = norm.NFC.String(s)
+    case normalizeNFKC:
+        s = norm.NFKC.String(s)
+    }
+    s = replaceControls(s, o.ControlChars)
+    if o.Sanitize == "" || o.Sanitize == "off" {
+        return s
+    }
+    policy, _ := ParsePolicy(o.Sanitize)
+    return Sanitize(s, policy)
+}
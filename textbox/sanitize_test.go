@@ -0,0 +1,159 @@
+package textbox
+
+import (
+    "bytes"
+    "io"
+    "strings"
+    "testing"
+)
+
+func TestSanitize(t *testing.T) {
+    tests := []struct {
+        name   string
+        line   string
+        strict string
+        sgr    string
+    }{
+        {"plain", "a\tb", "a\tb", "a\tb"},
+        {"sgr", "\x1b[31mred\x1b[0m", "red", "\x1b[31mred\x1b[0m"},
+        {"sgr colon", "\x1b[38:5:196mx", "x", "\x1b[38:5:196mx"},
+        {"csi", "a\x1b[2Jb\x1b[?25lc", "abc", "abc"},
+        {"osc bel", "a\x1b]0;title\x07b", "ab", "ab"},
+        {"osc st", "a\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\b", "alinkb", "alinkb"},
+        {"dcs", "a\x1bPq#0;2;0\x1b\\b", "ab", "ab"},
+        {"apc", "a\x1b_payload\x1b\\b", "ab", "ab"},
+        {"pm", "a\x1b^privacy\x1b\\b", "ab", "ab"},
+        {"sos", "a\x1bXstring\x1b\\b", "ab", "ab"},
+        {"dcs ignores bel", "a\x1bPx\x07y", "a", "a"},
+        {"nested osc", "a\x1b]0;x\x1b]0;y\x07b\x07c", "abc", "abc"},
+        {"sgr inside osc", "a\x1b]0;\x1b[31m\x07b", "ab", "ab"},
+        {"unterminated csi", "a\x1b[31", "a", "a"},
+        {"unterminated osc", "a\x1b]0;title", "a", "a"},
+        {"unterminated dcs", "a\x1bPdata", "a", "a"},
+        {"csi cut by control", "a\x1b[3\nb", "ab", "ab"},
+        {"bare esc", "a\x1b", "a", "a"},
+        {"two char escape", "a\x1bcb", "ab", "ab"},
+        {"c0", "a\x07b\x08c\rd", "abcd", "abcd"},
+        {"c1", "a\u009b31mb\u0085c\u009dd", "a31mbcd", "a31mbcd"},
+        {"del", "a\x7fb", "ab", "ab"},
+        {"invalid utf-8", "a\xffb\xc3", "ab", "ab"},
+    }
+    for _, tt := range tests {
+        if got := Sanitize(tt.line, Strict); got != tt.strict {
+            t.Errorf("%s: Sanitize(%q, Strict) = %q, want %q", tt.name, tt.line, got, tt.strict)
+        }
+        if got := Sanitize(tt.line, PreserveSGR); got != tt.sgr {
+            t.Errorf("%s: Sanitize(%q, PreserveSGR) = %q, want %q", tt.name, tt.line, got, tt.sgr)
+        }
+    }
+}
+
+func TestParsePolicy(t *testing.T) {
+    for name, want := range map[string]Policy{"strict": Strict, "sgr": PreserveSGR} {
+        if got, err := ParsePolicy(name); err != nil || got != want {
+            t.Errorf("ParsePolicy(%q) = %v, %v, want %v", name, got, err, want)
+        }
+    }
+    if _, err := ParsePolicy("none"); err == nil {
+        t.Error("ParsePolicy(\"none\") succeeded, want an error")
+    }
+}
+
+func TestRenderSanitizesTitleAndFooter(t *testing.T) {
+    for _, policy := range []string{"strict", "sgr"} {
+        opts := DefaultOptions()
+        opts.Sanitize = policy
+        opts.Title = "\x1b]0;evil\x07T\x1b[2J"
+        opts.Footer = "F\x1bP+q\x1b\\"
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"body\x1b]8;;x\x07"}, opts); err != nil {
+            t.Fatal(err)
+        }
+        out := buf.String()
+        if strings.Contains(out, "\x1b") || strings.Contains(out, "\a") {
+            t.Errorf("%s: output %q keeps escape sequences", policy, out)
+        }
+        for _, want := range []string{" T ", " F ", "body"} {
+            if !strings.Contains(out, want) {
+                t.Errorf("%s: output %q lacks %q", policy, out, want)
+            }
+        }
+    }
+}
+
+func TestReplaceControlsKeepsEscapeSequences(t *testing.T) {
+    line := "a\x07\x1b]8;;http://x\x07link\x1b]8;;\x07"
+    want := "a\u2407\x1b]8;;http://x\x07link\x1b]8;;\x07"
+    if got := replaceControls(line, controlsPictures); got != want {
+        t.Errorf("replaceControls(%q) = %q, want %q", line, got, want)
+    }
+}
+
+func TestRenderTitleTabsAndNewlines(t *testing.T) {
+    if got, want := expandTabs("a\tb\x1b[1m\tc"), "a       b\x1b[1m       c"; got != want {
+        t.Errorf("expandTabs = %q, want %q", got, want)
+    }
+
+    opts := DefaultOptions()
+    opts.Title = "a\tb"
+    var buf bytes.Buffer
+    if err := Render(&buf, []string{"x"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    if !strings.Contains(rows[0], "a       b") {
+        t.Errorf("top border %q does not expand the tab in the title", rows[0])
+    }
+    for _, row := range rows {
+        if visualLength(row) != visualLength(rows[1]) {
+            t.Errorf("row %q is %d wide, want %d", row, visualLength(row), visualLength(rows[1]))
+        }
+    }
+
+    for _, title := range []string{"a\nb", "a\r\nb"} {
+        opts.Title = title
+        if err := Render(io.Discard, []string{"x"}, opts); err == nil {
+            t.Errorf("Render with title %q succeeded, want an error", title)
+        }
+    }
+}
+
+func TestRenderNormalize(t *testing.T) {
+    for _, tc := range []struct {
+        form, line, want string
+    }{
+        {"", "e\u0301", "e\u0301"},
+        {"none", "Ｆｕｌｌ", "Ｆｕｌｌ"},
+        {"nfc", "e\u0301", "\u00e9"},
+        {"nfc", "Ｆｕｌｌ", "Ｆｕｌｌ"},
+        {"nfkc", "Ｆｕｌｌ", "Full"},
+    } {
+        opts := DefaultOptions()
+        opts.Normalize, opts.Title = tc.form, tc.line
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{tc.line}, opts); err != nil {
+            t.Fatal(err)
+        }
+        rows := strings.Split(buf.String(), "\n")
+        if !strings.Contains(rows[0], " "+tc.want+" ") || !strings.Contains(rows[1], " "+tc.want+" ") {
+            t.Errorf("normalize %q: got\n%s\nwant the title and line %q", tc.form, buf.String(), tc.want)
+        }
+    }
+    opts := DefaultOptions()
+    opts.Normalize = "nfd"
+    if err := Render(io.Discard, []string{"x"}, opts); err == nil {
+        t.Error("Render with normalization nfd succeeded, want an error")
+    }
+}
+
+func TestRenderContentTabs(t *testing.T) {
+    var buf bytes.Buffer
+    if err := Render(&buf, []string{"a\tb", "abcdefghi\tj"}, DefaultOptions()); err != nil {
+        t.Fatal(err)
+    }
+    // Tab stops count from the start of the line, not of the border.
+    want := "┌───────────────────┐\n│ a       b         │\n│ abcdefghi       j │\n└───────────────────┘\n"
+    if buf.String() != want {
+        t.Errorf("box:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
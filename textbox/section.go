@@ -0,0 +1,81 @@
+package textbox
+
+import (
+    "io"
+    "strings"
+)
+
+// section is a heading drawn as a labeled divider above the content row
+// at, or above the bottom border if at is the number of rows.
+type section struct {
+    at    int
+    decor string
+}
+
+// splitSections removes the lines starting with marker from lines and
+// returns the rest with the headings they name, each placed before the
+// line that followed it.
+func splitSections(lines []string, marker string) ([]string, []section) {
+    var content []string
+    var sections []section
+    for _, line := range lines {
+        if heading, ok := strings.CutPrefix(line, marker); ok {
+            sections = append(sections, section{at: len(content), decor: strings.TrimSpace(heading)})
+            continue
+        }
+        content = append(content, line)
+    }
+    return content, sections
+}
+
+// eachSection applies f to the lines between the headings and moves the
+// headings with the rows their lines become.
+func eachSection(lines []string, sections []section, f func([]string) []string) ([]string, []section) {
+    if len(sections) == 0 {
+        return f(lines), nil
+    }
+    var out []string
+    moved := make([]section, len(sections))
+    start := 0
+    for i, s := range sections {
+        out = append(out, f(lines[start:s.at])...)
+        moved[i], start = section{at: len(out), decor: s.decor}, s.at
+    }
+    return append(out, f(lines[start:])...), moved
+}
+
+// headingsAt returns the decorated headings drawn above content row i.
+func (l boxLayout) headingsAt(i int) []string {
+    var decors []string
+    for _, s := range l.sections {
+        if s.at == i {
+            decors = append(decors, s.decor)
+        }
+    }
+    return decors
+}
+
+// drawHeadings writes the labeled dividers above content row i.
+func (l boxLayout) drawHeadings(w io.Writer, i int) error {
+    left, right := teeGlyphs(l.style)
+    for _, decor := range l.headingsAt(i) {
+        if err := l.drawTitleRow(w, left, right, decor, l.shadowCell()); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// headingDecor frames heading with the title caps of style in the border
+// and title colors. A width above 0 cuts the decor to it with an ellipsis.
+// An empty heading leaves a plain divider.
+func headingDecor(heading string, style BoxStyle, width int, borderColor, titleColor ANSIColor) string {
+    caps := Colorize(style.TitleLeft, borderColor) + "  " + Colorize(style.TitleRight, borderColor)
+    if width > 0 {
+        heading = clipTitle(heading, width-visualLength(caps))
+    }
+    if heading == "" {
+        return ""
+    }
+    return Colorize(style.TitleLeft, borderColor) + " " + Colorize(heading, titleColor) + " " + Colorize(style.TitleRight, borderColor)
+}
@@ -0,0 +1,62 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// ShadowStyle selects the shadow drawn with a box.
+type ShadowStyle int
+
+// Shadow styles.
+const (
+    // NoShadow draws no shadow of its own; the theme may still add one.
+    NoShadow ShadowStyle = iota
+    // DropShadow shades the cells below and to the right of the box.
+    DropShadow
+    // InnerShadow shades the row below the top border and the column right
+    // of the left border, as if the interior were sunk.
+    InnerShadow
+)
+
+// shadowNames are the names of the shadow styles, indexed by style.
+var shadowNames = []string{"none", "drop", "inner"}
+
+func (s ShadowStyle) String() string {
+    if s >= 0 && int(s) < len(shadowNames) {
+        return shadowNames[s]
+    }
+    return fmt.Sprintf("ShadowStyle(%d)", int(s))
+}
+
+// ParseShadowStyle returns the shadow style named none, drop or inner.
+func ParseShadowStyle(name string) (ShadowStyle, error) {
+    for i, n := range shadowNames {
+        if strings.EqualFold(name, n) {
+            return ShadowStyle(i), nil
+        }
+    }
+    return NoShadow, fmt.Errorf("invalid shadow %q, use none, drop or inner", name)
+}
+
+// MarshalText encodes the style as its name.
+func (s ShadowStyle) MarshalText() ([]byte, error) {
+    if s < 0 || int(s) >= len(shadowNames) {
+        return nil, fmt.Errorf("invalid shadow style %d", int(s))
+    }
+    return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a shadow style name.
+func (s *ShadowStyle) UnmarshalText(text []byte) error {
+    parsed, err := ParseShadowStyle(string(text))
+    if err == nil {
+        *s = parsed
+    }
+    return err
+}
+
+// Set implements flag.Value.
+func (s *ShadowStyle) Set(name string) error {
+    return s.UnmarshalText([]byte(name))
+}
@@ -0,0 +1,84 @@
+package textbox
+
+import (
+    "errors"
+    "io"
+    "strings"
+    "sync"
+)
+
+// ErrClosed is returned when writing to a closed Streamer.
+var ErrClosed = errors.New("streamer closed")
+
+// Streamer draws a box line by line as the lines become available. The box
+// width is fixed when the Streamer is created: every line is wrapped and cut
+// to it and written immediately.
+//
+// The methods of a Streamer are safe for concurrent use. WriteLine and Close
+// are serialized, so a line is either written completely before the bottom
+// border or rejected with ErrClosed.
+type Streamer struct {
+    mu     sync.Mutex
+    w      io.Writer
+    l      boxLayout
+    opts   Options
+    rows   int
+    closed bool
+}
+
+// NewStreamer writes the top border of a box to w and returns a Streamer for
+// its rows. The options must set a width.
+func NewStreamer(w io.Writer, opts ...Option) (*Streamer, error) {
+    o := NewOptions(opts...)
+    if o.Width <= 0 {
+        return nil, errors.New("a streamer needs a width")
+    }
+    o.Reflow, o.Height, o.MaxLines, o.Reverse = false, 0, 0, false
+    l, err := prepare(nil, o)
+    if err != nil {
+        return nil, err
+    }
+    s := &Streamer{w: w, l: l, opts: o}
+    return s, l.drawTop(w)
+}
+
+// WriteLine wraps line according to the options, cuts it to the box width
+// and writes the resulting rows.
+func (s *Streamer) WriteLine(line string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed {
+        return ErrClosed
+    }
+    if s.opts.SectionMarker != "" {
+        if heading, ok := strings.CutPrefix(s.opts.sanitize([]string{line})[0], s.opts.SectionMarker); ok {
+            left, right := teeGlyphs(s.l.style)
+            width := max(s.l.innerWidth-2*visualLength(s.l.style.Horizontal), 1)
+            decor := headingDecor(strings.TrimSpace(heading), s.l.style, width, s.l.borderColor, s.l.titleColor)
+            return s.l.drawTitleRow(s.w, left, right, decor, s.l.shadowCell())
+        }
+    }
+    width := s.l.innerWidth - 2
+    for _, row := range truncateLines(wrapLines(s.opts.sanitize([]string{line}), s.opts), width) {
+        if err := s.l.drawRow(s.w, row); err != nil {
+            return err
+        }
+        if s.rows++; s.rows == s.l.divider {
+            if err := s.l.drawDivider(s.w); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// Close writes the bottom border. Closing a closed Streamer does nothing.
+func (s *Streamer) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed {
+        return nil
+    }
+    s.closed = true
+    return s.l.drawBottom(s.w)
+}
@@ -0,0 +1,90 @@
+package textbox
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// streamRows writes lines through a Streamer and returns the rows written.
+func streamRows(t *testing.T, lines []string, opts Options) []string {
+    t.Helper()
+    var buf bytes.Buffer
+    s, err := NewStreamer(&buf, WithOptions(opts))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, line := range lines {
+        if err := s.WriteLine(line); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := s.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestStreamerTitleAlign(t *testing.T) {
+    opts := DefaultOptions()
+    opts.Title, opts.Width, opts.TitleAlign, opts.TitleOnlyAlign = "T", 14, Left, Right
+    rows := streamRows(t, []string{"body"}, opts)
+    if want := "┌─┘ T └──────┐"; rows[0] != want {
+        t.Errorf("top border %q, want %q", rows[0], want)
+    }
+}
+
+func TestStreamerMatchesRender(t *testing.T) {
+    lines := []string{"header", "a line long enough to be wrapped", "short", "tail"}
+    tests := []struct {
+        name string
+        set  func(*Options)
+    }{
+        {"width", func(o *Options) {}},
+        {"title", func(o *Options) { o.Title, o.Footer, o.FooterAlign = "Log", "end", Right }},
+        {"center", func(o *Options) { o.TextAlign = Center }},
+        {"right", func(o *Options) { o.TextAlign = Right }},
+        {"soft wrap", func(o *Options) { o.Wrap = 10 }},
+        {"hard wrap", func(o *Options) { o.Wrap, o.WrapMode = 10, Hard }},
+        {"cut", func(o *Options) { o.Width = 12 }},
+        {"divider", func(o *Options) { o.TitleSep = true }},
+    }
+    for _, tt := range tests {
+        opts := DefaultOptions()
+        opts.Width = 20
+        tt.set(&opts)
+
+        var buf bytes.Buffer
+        if err := Render(&buf, lines, opts); err != nil {
+            t.Fatal(err)
+        }
+        want := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+        got := streamRows(t, lines, opts)
+        if strings.Join(got, "\n") != strings.Join(want, "\n") {
+            t.Errorf("%s: streamed\n%s\nwant\n%s", tt.name, strings.Join(got, "\n"), strings.Join(want, "\n"))
+        }
+    }
+}
+
+func TestStreamerClose(t *testing.T) {
+    var buf bytes.Buffer
+    s, err := NewStreamer(&buf, WithWidth(8))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := s.Close(); err != nil {
+        t.Fatal(err)
+    }
+    if err := s.Close(); err != nil {
+        t.Errorf("second Close = %v, want nil", err)
+    }
+    if err := s.WriteLine("late"); err != ErrClosed {
+        t.Errorf("WriteLine after Close = %v, want ErrClosed", err)
+    }
+    if want := "┌──────┐\n└──────┘\n"; buf.String() != want {
+        t.Errorf("output %q, want %q", buf.String(), want)
+    }
+    if _, err := NewStreamer(&buf); err == nil {
+        t.Error("NewStreamer without a width succeeded, want an error")
+    }
+}
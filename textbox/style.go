@@ -0,0 +1,501 @@
+package textbox
+
+import (
+    "cmp"
+    "errors"
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "sync"
+    "unicode"
+
+    "github.com/rivo/uniseg"
+)
+
+// BoxStyle contains the characters for the various frame components.
+// TitleLeft and TitleRight are the caps framing the title in the top border.
+// The optional junctions TeeLeft and TeeRight join dividers to the sides and
+// Cross joins them to the column lines of tables; empty ones are chosen to
+// match the horizontal and vertical glyphs.
+type BoxStyle struct {
+    TopLeft     string `json:"top_left"`
+    TopRight    string `json:"top_right"`
+    BottomLeft  string `json:"bottom_left"`
+    BottomRight string `json:"bottom_right"`
+    Horizontal  string `json:"horizontal"`
+    Vertical    string `json:"vertical"`
+    TitleLeft   string `json:"title_left"`
+    TitleRight  string `json:"title_right"`
+    TeeLeft     string `json:"tee_left,omitempty"`
+    TeeRight    string `json:"tee_right,omitempty"`
+    Cross       string `json:"cross,omitempty"`
+}
+
+// NewBoxStyle builds a style from its eight components in the order top
+// left, top right, bottom left, bottom right, horizontal, vertical, title
+// left and title right. A single glyph is used for every component. Every
+// glyph must be at least one column wide.
+func NewBoxStyle(glyphs ...string) (BoxStyle, error) {
+    g := glyphs
+    if len(g) == 1 {
+        g = []string{g[0], g[0], g[0], g[0], g[0], g[0], g[0], g[0]}
+    }
+    if len(g) != 8 {
+        return BoxStyle{}, fmt.Errorf("a style needs 1 or 8 glyphs, got %d", len(g))
+    }
+    if err := checkGlyphs(g); err != nil {
+        return BoxStyle{}, err
+    }
+    return BoxStyle{
+        TopLeft: g[0], TopRight: g[1], BottomLeft: g[2], BottomRight: g[3],
+        Horizontal: g[4], Vertical: g[5], TitleLeft: g[6], TitleRight: g[7],
+    }, nil
+}
+
+// checkGlyphs fails for missing glyphs, control characters and glyphs
+// without width, such as combining marks, which cannot be laid out.
+func checkGlyphs(glyphs []string) error {
+    for _, g := range glyphs {
+        if g == "" {
+            return errors.New("all eight glyphs are required")
+        }
+        if strings.ContainsFunc(g, unicode.IsControl) {
+            return fmt.Errorf("glyph %q (%s) holds a control character", g, codepoints(g))
+        }
+        if visualLength(g) < 1 {
+            return fmt.Errorf("glyph %q (%s) is zero columns wide", g, codepoints(g))
+        }
+    }
+    return nil
+}
+
+// codepoints returns the code points of s as U+0041 U+030A.
+func codepoints(s string) string {
+    var cps []string
+    for _, r := range s {
+        cps = append(cps, fmt.Sprintf("%U", r))
+    }
+    return strings.Join(cps, " ")
+}
+
+// CustomGlyph returns the glyph style 4 draws for the Options.Char value
+// char: char without surrounding whitespace, which must be a single
+// grapheme cluster such as a wide 中 or ❤️ with its variation selector. It
+// fails with ErrCustomChar for none or several characters, and names the
+// code points of control characters and characters without width.
+func CustomGlyph(char string) (string, error) {
+    glyph := strings.TrimSpace(char)
+    if uniseg.GraphemeClusterCount(glyph) != 1 {
+        return "", ErrCustomChar
+    }
+    if err := checkGlyphs([]string{glyph}); err != nil {
+        return "", err
+    }
+    return glyph, nil
+}
+
+// Glyphs returns the frame components in the order NewBoxStyle takes them.
+func (s BoxStyle) Glyphs() []string {
+    return []string{
+        s.TopLeft, s.TopRight, s.BottomLeft, s.BottomRight,
+        s.Horizontal, s.Vertical, s.TitleLeft, s.TitleRight,
+    }
+}
+
+// styleFields are the names of the components in String, in the order of
+// Glyphs.
+var styleFields = []string{"TL", "TR", "BL", "BR", "H", "V", "TitleL", "TitleR"}
+
+// junctionFields are the names of the optional junctions in String.
+var junctionFields = []string{"TeeL", "TeeR", "Cross"}
+
+// junctions returns the optional junctions of s in the order of
+// junctionFields.
+func (s *BoxStyle) junctions() []*string {
+    return []*string{&s.TeeLeft, &s.TeeRight, &s.Cross}
+}
+
+// String returns the components of s as
+// BoxStyle{TL:┌ TR:┐ BL:└ BR:┘ H:─ V:│ TitleL:┘ TitleR:└}, followed by the
+// junctions that are set.
+func (s BoxStyle) String() string {
+    parts := make([]string, len(styleFields))
+    for i, g := range s.Glyphs() {
+        parts[i] = styleFields[i] + ":" + quoteGlyph(g)
+    }
+    for i, g := range s.junctions() {
+        if *g != "" {
+            parts = append(parts, junctionFields[i]+":"+quoteGlyph(*g))
+        }
+    }
+    return "BoxStyle{" + strings.Join(parts, " ") + "}"
+}
+
+// StyleWarning is returned by ParseBoxStyle together with a usable style
+// when the notation contained fields it does not know.
+type StyleWarning struct {
+    Fields []string
+}
+
+func (w *StyleWarning) Error() string {
+    return "unknown style fields ignored: " + strings.Join(w.Fields, ", ")
+}
+
+// quoteGlyph returns g as written in the String notation: glyphs that
+// would end a field early, by holding a space or a closing brace or by
+// starting with a quote, are quoted.
+func quoteGlyph(g string) string {
+    if g == "" || strings.HasPrefix(g, `"`) || strings.Contains(g, "}") || strings.ContainsFunc(g, unicode.IsSpace) {
+        return strconv.Quote(g)
+    }
+    return g
+}
+
+// styleFieldPattern matches one NAME:GLYPH field of the String notation.
+// The glyph is either a run up to the next space or brace, or a Go quoted
+// string.
+var styleFieldPattern = regexp.MustCompile(`(\w+)\s*:\s*("(?:[^"\\]|\\.)*"|[^\s}]+)`)
+
+// ParseBoxStyle parses the notation written by String. The BoxStyle{}
+// wrapper is optional, the fields may come in any order and their names are
+// case insensitive. Glyphs holding spaces or braces are quoted as a Go
+// string. Unknown fields are ignored and reported with a *StyleWarning,
+// missing fields are an error.
+func ParseBoxStyle(s string) (BoxStyle, error) {
+    // The junctions follow the eight components.
+    fields := append(append([]string(nil), styleFields...), junctionFields...)
+    glyphs := make([]string, len(fields))
+    var unknown []string
+    for _, m := range styleFieldPattern.FindAllStringSubmatch(strings.TrimPrefix(strings.TrimSpace(s), "BoxStyle{"), -1) {
+        i := -1
+        for j, name := range fields {
+            if strings.EqualFold(m[1], name) {
+                i = j
+            }
+        }
+        if i < 0 {
+            unknown = append(unknown, m[1])
+            continue
+        }
+        g := m[2]
+        if strings.HasPrefix(g, `"`) {
+            q, err := strconv.Unquote(g)
+            if err != nil {
+                return BoxStyle{}, fmt.Errorf("style %q: field %s: bad quoted glyph %s", s, fields[i], g)
+            }
+            g = q
+        }
+        glyphs[i] = g
+    }
+    glyphs, junctions := glyphs[:len(styleFields)], glyphs[len(styleFields):]
+    for i, g := range glyphs {
+        if g == "" {
+            return BoxStyle{}, fmt.Errorf("style %q: missing field %s", s, styleFields[i])
+        }
+    }
+    style, err := NewBoxStyle(glyphs...)
+    if err != nil {
+        return style, err
+    }
+    for i, g := range style.junctions() {
+        if junctions[i] == "" {
+            continue
+        }
+        if err := checkGlyphs(junctions[i:i+1]); err != nil {
+            return BoxStyle{}, fmt.Errorf("style %q: field %s: %v", s, junctionFields[i], err)
+        }
+        *g = junctions[i]
+    }
+    if len(unknown) > 0 {
+        err = &StyleWarning{Fields: unknown}
+    }
+    return style, err
+}
+
+// isStyleNotation reports whether name is written in the String notation
+// rather than naming a style.
+func isStyleNotation(name string) bool {
+    return strings.Contains(name, ":")
+}
+
+// Different styles to choose from.
+var builtinStyles = []struct {
+    name  string
+    style BoxStyle
+}{
+    {"single", BoxStyle{
+        TopLeft: "┌", TopRight: "┐", BottomLeft: "└", BottomRight: "┘",
+        Horizontal: "─", Vertical: "│", TitleLeft: "┘", TitleRight: "└",
+    }},
+    {"round", BoxStyle{
+        TopLeft: "╭", TopRight: "╮", BottomLeft: "╰", BottomRight: "╯",
+        Horizontal: "─", Vertical: "│", TitleLeft: "╯", TitleRight: "╰",
+    }},
+    {"double", BoxStyle{
+        TopLeft: "╔", TopRight: "╗", BottomLeft: "╚", BottomRight: "╝",
+        Horizontal: "═", Vertical: "║", TitleLeft: "╝", TitleRight: "╚",
+    }},
+}
+
+// CustomStyle is the style number that draws every component with
+// Options.Char.
+const CustomStyle = "4"
+
+// registry holds the styles that can be selected by name. The built-in
+// styles can also be selected by their number, starting at 1.
+var registry = struct {
+    sync.RWMutex
+    styles map[string]BoxStyle
+    names  []string
+}{styles: make(map[string]BoxStyle)}
+
+func init() {
+    for _, b := range builtinStyles {
+        registry.styles[b.name] = b.style
+        registry.names = append(registry.names, b.name)
+    }
+}
+
+// RegisterStyle makes s selectable as Options.Style under name. It fails if
+// a component of s is empty or zero columns wide or the name is already
+// taken. It is safe for
+// concurrent use.
+func RegisterStyle(name string, s BoxStyle) error {
+    if name == "" || strings.ContainsAny(name, " \t\n") {
+        return fmt.Errorf("invalid style name %q", name)
+    }
+    if err := checkGlyphs(s.Glyphs()); err != nil {
+        return fmt.Errorf("style %q: %v", name, err)
+    }
+
+    registry.Lock()
+    defer registry.Unlock()
+    if _, ok := lookupStyle(name); ok || name == CustomStyle {
+        return fmt.Errorf("style %q already exists", name)
+    }
+    registry.styles[name] = s
+    registry.names = append(registry.names, name)
+    return nil
+}
+
+// Styles returns the names of all registered styles, built-in styles first.
+func Styles() []string {
+    registry.RLock()
+    defer registry.RUnlock()
+    return append([]string(nil), registry.names...)
+}
+
+// LookupStyle returns the style registered as name or numbered name.
+func LookupStyle(name string) (BoxStyle, bool) {
+    registry.RLock()
+    defer registry.RUnlock()
+    return lookupStyle(name)
+}
+
+// lookupStyle is LookupStyle with the registry lock held.
+func lookupStyle(name string) (BoxStyle, bool) {
+    for i, b := range builtinStyles {
+        if name == fmt.Sprint(i+1) {
+            return b.style, true
+        }
+    }
+    s, ok := registry.styles[name]
+    return s, ok
+}
+
+// Errors returned for an unusable style selection.
+var (
+    ErrCustomChar   = errors.New("style 4 needs exactly one character")
+    ErrUnknownStyle = errors.New("unknown style")
+    ErrUnknownTheme = errors.New("unknown theme")
+)
+
+// style returns the style selected by o.Style and o.Char.
+func (o Options) style() (BoxStyle, error) {
+    if o.Theme != "" {
+        t, ok := LookupTheme(o.Theme)
+        if !ok {
+            return BoxStyle{}, fmt.Errorf("%w %q", ErrUnknownTheme, o.Theme)
+        }
+        return t.BoxStyle, nil
+    }
+    if o.Style == CustomStyle {
+        glyph, err := CustomGlyph(o.Char)
+        if err != nil {
+            return BoxStyle{}, err
+        }
+        return NewBoxStyle(glyph)
+    }
+    if s, ok := LookupStyle(o.Style); ok {
+        return s, nil
+    }
+    if isStyleNotation(o.Style) {
+        s, err := ParseBoxStyle(o.Style)
+        var warning *StyleWarning
+        if errors.As(err, &warning) {
+            err = nil
+        }
+        return s, err
+    }
+    return BoxStyle{}, fmt.Errorf("%w %q", ErrUnknownStyle, o.Style)
+}
+
+// topCorners maps vertical and horizontal glyphs to the top corners joining
+// them, for a top border drawn in another style than the sides.
+var topCorners = map[[2]string][2]string{
+    {"│", "─"}: {"┌", "┐"},
+    {"│", "━"}: {"┍", "┑"},
+    {"┃", "─"}: {"┎", "┒"},
+    {"┃", "━"}: {"┏", "┓"},
+    {"║", "═"}: {"╔", "╗"},
+    {"║", "─"}: {"╓", "╖"},
+    {"│", "═"}: {"╒", "╕"},
+    {"|", "-"}: {"+", "+"},
+}
+
+// topStyle returns s with the top border, corners and title caps of the
+// style selected by o.TitleStyle, or s itself if there is none. If the
+// sides differ, the top corners are replaced by junctions with the sides of
+// s where one is known.
+// The title style must be as wide as s, so that the borders line up.
+func (o Options) topStyle(s BoxStyle) (BoxStyle, error) {
+    if o.TitleStyle == "" {
+        return s, nil
+    }
+    t := o
+    t.Theme, t.Style = "", o.TitleStyle
+    title, err := t.style()
+    if err != nil {
+        return BoxStyle{}, fmt.Errorf("title style: %w", err)
+    }
+    if visualLength(title.Horizontal) != visualLength(s.Horizontal) ||
+        visualLength(title.TopLeft) != visualLength(s.TopLeft) ||
+        visualLength(title.TopRight) != visualLength(s.TopRight) {
+        return BoxStyle{}, fmt.Errorf("title style %q is not as wide as the style of the box", o.TitleStyle)
+    }
+    top := s
+    top.TopLeft, top.TopRight, top.Horizontal = title.TopLeft, title.TopRight, title.Horizontal
+    top.TitleLeft, top.TitleRight = title.TitleLeft, title.TitleRight
+    if c, ok := topCorners[[2]string{s.Vertical, top.Horizontal}]; ok && title.Vertical != s.Vertical {
+        top.TopLeft, top.TopRight = c[0], c[1]
+    }
+    return top, nil
+}
+
+// tees maps vertical and horizontal glyphs to the left and right junction
+// glyphs joining a divider to the border.
+var tees = map[[2]string][2]string{
+    {"│", "─"}: {"├", "┤"},
+    {"│", "━"}: {"┝", "┥"},
+    {"┃", "─"}: {"┠", "┨"},
+    {"┃", "━"}: {"┣", "┫"},
+    {"║", "═"}: {"╠", "╣"},
+    {"║", "─"}: {"╟", "╢"},
+    {"│", "═"}: {"╞", "╡"},
+    {"|", "-"}: {"+", "+"},
+}
+
+// teeGlyphs returns the junctions of a divider of s with its left and right
+// border: those of s, if set, or else the known junction of its glyphs.
+// Without one the vertical glyph is used.
+func teeGlyphs(s BoxStyle) (left, right string) {
+    left, right = s.Vertical, s.Vertical
+    if t, ok := tees[[2]string{s.Vertical, s.Horizontal}]; ok {
+        left, right = t[0], t[1]
+    }
+    return cmp.Or(s.TeeLeft, left), cmp.Or(s.TeeRight, right)
+}
+
+// crosses maps vertical and horizontal glyphs to the junction where a
+// divider crosses a column line.
+var crosses = map[[2]string]string{
+    {"│", "─"}: "┼",
+    {"│", "━"}: "┿",
+    {"┃", "─"}: "╂",
+    {"┃", "━"}: "╋",
+    {"║", "═"}: "╬",
+    {"║", "─"}: "╫",
+    {"│", "═"}: "╪",
+    {"|", "-"}: "+",
+}
+
+// crossGlyph returns the junction of a divider of s with a column line: the
+// Cross of s, if set, or else the known junction of its glyphs. Without
+// one the vertical glyph is used.
+func crossGlyph(s BoxStyle) string {
+    if s.Cross != "" {
+        return s.Cross
+    }
+    if c, ok := crosses[[2]string{s.Vertical, s.Horizontal}]; ok {
+        return c
+    }
+    return s.Vertical
+}
+
+// asciiGlyphs are the ASCII replacements of non-ASCII horizontal and
+// vertical glyphs that differ from the defaults "-" and "|".
+var asciiGlyphs = map[string]string{"═": "=", "█": "#", "▀": "#", "▄": "#"}
+
+// asciiStyle transliterates the components of s to ASCII: corners, title
+// caps and junctions become "+", lines "-" or "|". Glyphs that are ASCII
+// already are kept.
+func asciiStyle(s BoxStyle) BoxStyle {
+    ascii := func(g, fallback string) string {
+        if isASCII(g) {
+            return g
+        }
+        if a, ok := asciiGlyphs[g]; ok && fallback != "+" {
+            return a
+        }
+        return fallback
+    }
+    return BoxStyle{
+        TopLeft: ascii(s.TopLeft, "+"), TopRight: ascii(s.TopRight, "+"),
+        BottomLeft: ascii(s.BottomLeft, "+"), BottomRight: ascii(s.BottomRight, "+"),
+        Horizontal: ascii(s.Horizontal, "-"), Vertical: ascii(s.Vertical, "|"),
+        TitleLeft: ascii(s.TitleLeft, "+"), TitleRight: ascii(s.TitleRight, "+"),
+        TeeLeft: ascii(s.TeeLeft, "+"), TeeRight: ascii(s.TeeRight, "+"), Cross: ascii(s.Cross, "+"),
+    }
+}
+
+// isASCII reports whether s consists of printable ASCII characters.
+func isASCII(s string) bool {
+    for _, r := range s {
+        if r < ' ' || r > '~' {
+            return false
+        }
+    }
+    return true
+}
+
+// fitsWidth reports whether style draws a box of exactly opts.Width columns
+// without padding the border or the title.
+func fitsWidth(style BoxStyle, opts Options) bool {
+    vertical := visualLength(style.Vertical)
+    if visualLength(style.TopLeft)+visualLength(style.TopRight) != 2*vertical ||
+        visualLength(style.BottomLeft)+visualLength(style.BottomRight) != 2*vertical {
+        return false
+    }
+    inner := opts.Width - 2*vertical
+    title := 0
+    if opts.Title != "" {
+        title = visualLength(titleDecoration(style, opts.Title))
+    }
+    glyphWidth := max(visualLength(style.Horizontal), 1)
+    return fitWidth(inner, title, glyphWidth) == inner && (inner-title)%glyphWidth == 0
+}
+
+// autoStyle returns style if it fits opts.Width, otherwise the first
+// registered style that does. Without a fitting style, style is kept.
+func autoStyle(style BoxStyle, opts Options) BoxStyle {
+    if opts.Width <= 0 || fitsWidth(style, opts) {
+        return style
+    }
+    for _, name := range Styles() {
+        if candidate, ok := LookupStyle(name); ok && fitsWidth(candidate, opts) {
+            return candidate
+        }
+    }
+    return style
+}
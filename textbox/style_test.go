@@ -0,0 +1,102 @@
+package textbox
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestZeroWidthGlyphsRejected(t *testing.T) {
+    for _, g := range []string{"́", "​"} {
+        if _, err := NewBoxStyle(g); err == nil {
+            t.Errorf("NewBoxStyle(%q) succeeded, want an error", g)
+        }
+        if _, err := ParseBoxStyle("TL:+ TR:+ BL:+ BR:+ H:" + g + " V:| TitleL:+ TitleR:+"); err == nil {
+            t.Errorf("ParseBoxStyle with H:%q succeeded, want an error", g)
+        }
+        style := builtinStyles[0].style
+        style.Horizontal = g
+        if err := RegisterStyle("zero-width-test", style); err == nil {
+            t.Errorf("RegisterStyle with horizontal %q succeeded, want an error", g)
+        }
+
+        opts := DefaultOptions()
+        opts.Style, opts.Char = CustomStyle, g
+        var buf bytes.Buffer
+        if err := Render(&buf, []string{"a"}, opts); err == nil {
+            t.Errorf("Render with -f %q succeeded, want an error", g)
+        }
+    }
+}
+
+func TestParseBoxStyleRoundTrip(t *testing.T) {
+    styles := []BoxStyle{{
+        TopLeft: "{", TopRight: "}", BottomLeft: " ", BottomRight: `"`,
+        Horizontal: `\`, Vertical: ":", TitleLeft: "} ", TitleRight: `"}"`,
+    }, {
+        TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+        Horizontal: "-", Vertical: "|", TitleLeft: "[", TitleRight: "]",
+        TeeLeft: ">", Cross: "*",
+    }}
+    for _, b := range builtinStyles {
+        styles = append(styles, b.style)
+    }
+    for _, want := range styles {
+        got, err := ParseBoxStyle(want.String())
+        if err != nil {
+            t.Errorf("ParseBoxStyle(%q): %v", want.String(), err)
+            continue
+        }
+        if got != want {
+            t.Errorf("ParseBoxStyle(%q) = %v, want %v", want.String(), got, want)
+        }
+    }
+}
+
+func TestParseBoxStyleBadQuote(t *testing.T) {
+    if _, err := ParseBoxStyle(`TL:"\q" TR:+ BL:+ BR:+ H:- V:| TitleL:+ TitleR:+`); err == nil {
+        t.Error("ParseBoxStyle with a bad quoted glyph succeeded, want an error")
+    }
+}
+
+func TestCustomGlyph(t *testing.T) {
+    for _, tc := range []struct {
+        char, want string
+        err        string
+    }{
+        {" x ", "x", ""},
+        {"中", "中", ""},
+        {"❤️", "❤️", ""},
+        {"é", "é", ""},
+        {"", "", ErrCustomChar.Error()},
+        {" ", "", ErrCustomChar.Error()},
+        {"ab", "", ErrCustomChar.Error()},
+        {"\a", "", "U+0007"},
+        {"\x7f", "", "U+007F"},
+        {"​", "", "U+200B"},
+    } {
+        got, err := CustomGlyph(tc.char)
+        switch {
+        case tc.err == "" && err != nil:
+            t.Errorf("CustomGlyph(%q): %v", tc.char, err)
+        case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+            t.Errorf("CustomGlyph(%q) error = %v, want one mentioning %s", tc.char, err, tc.err)
+        case got != tc.want:
+            t.Errorf("CustomGlyph(%q) = %q, want %q", tc.char, got, tc.want)
+        }
+    }
+
+    // Wide glyphs keep the rows of the box equally wide.
+    opts := DefaultOptions()
+    opts.Style, opts.Char = CustomStyle, "❤️"
+    var buf bytes.Buffer
+    if err := Render(&buf, []string{"odd"}, opts); err != nil {
+        t.Fatal(err)
+    }
+    rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+    for _, row := range rows {
+        if visualLength(row) != visualLength(rows[0]) {
+            t.Errorf("row %q is %d wide, want %d", row, visualLength(row), visualLength(rows[0]))
+        }
+    }
+}
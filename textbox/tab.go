@@ -0,0 +1,84 @@
+package textbox
+
+import (
+    "fmt"
+    "io"
+    "strings"
+)
+
+// upTees maps horizontal glyphs to the junction joining a vertical line
+// from above, used where the sides of a title tab meet the top border.
+var upTees = map[string]string{
+    "─": "┴",
+    "━": "┻",
+    "═": "╩",
+    "-": "+",
+}
+
+// upTee returns the junction of a title tab side with the top border of s.
+// Without a known junction the horizontal glyph is used.
+func upTee(s BoxStyle) string {
+    if t, ok := upTees[s.Horizontal]; ok {
+        return t
+    }
+    return s.Horizontal
+}
+
+// tabRows is the number of rows a title tab adds above the top border.
+const tabRows = 2
+
+// tabCells returns the number of horizontal glyphs inside the title tab.
+func (l boxLayout) tabCells() int {
+    glyphWidth := visualLength(l.style.Horizontal)
+    return (visualLength(l.titleDecor) + glyphWidth - 1) / glyphWidth
+}
+
+// drawTab writes the title tab and the top border it stands on. The tab is
+// placed by the title alignment; its sides join the border with junctions.
+func (l boxLayout) drawTab(w io.Writer, gap string) error {
+    style := l.style
+    glyphWidth := visualLength(style.Horizontal)
+    inner := l.tabCells()
+    // The border is a row of cells: the corners and the horizontal glyphs
+    // between them. The tab covers inner of them and the two at its sides.
+    cells := l.innerWidth/glyphWidth + 2
+    before, _ := alignFill(cells-inner-2, l.titleAlign)
+    left, right := before, before+inner+1
+
+    indent := blank(0, l.overlay)
+    if left > 0 {
+        indent = blank(visualLength(style.TopLeft)+(left-1)*glyphWidth, l.overlay)
+    }
+    if _, err := fmt.Fprintf(w, "%s%s\n", indent,
+        l.border(style.TopLeft+repeatChar(style.Horizontal, inner)+style.TopRight)); err != nil {
+        return err
+    }
+    if _, err := fmt.Fprintf(w, "%s%s%s%s%s\n", indent,
+        l.border(style.Vertical),
+        l.titleDecor,
+        blank(inner*glyphWidth-visualLength(l.titleDecor), l.overlay),
+        l.border(style.Vertical)); err != nil {
+        return err
+    }
+
+    leftTee, rightTee := teeGlyphs(style)
+    var border strings.Builder
+    for i := 0; i < cells; i++ {
+        switch {
+        case i == left && i == 0:
+            border.WriteString(leftTee)
+        case i == right && i == cells-1:
+            border.WriteString(rightTee)
+        case i == left || i == right:
+            border.WriteString(upTee(style))
+        case i == 0:
+            border.WriteString(style.TopLeft)
+        case i == cells-1:
+            border.WriteString(style.TopRight)
+        default:
+            border.WriteString(style.Horizontal)
+        }
+    }
+    _, err := fmt.Fprintf(w, "%s%s\n", l.border(border.String()), gap)
+    return err
+}
@@ -0,0 +1,201 @@
+package textbox
+
+import (
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// RenderTable writes rows as columns inside a box framed according to opts.
+// The cells are placed in their columns by opts.ColumnAlign, which must not
+// name more columns than the table has; columns beyond it are aligned left
+// rather than repeating its last alignment. With opts.TableHeader the first row
+// is ruled off from the rest. Columns are no wider than opts.ColumnMaxWidth
+// and opts.ColumnMaxWidths allow and, with opts.CellWrap and a fixed width,
+// are narrowed further to fit it. Cells wider than their column wrap onto
+// further rows.
+func RenderTable(w io.Writer, rows [][]string, opts Options) error {
+    style, err := opts.style()
+    if err != nil {
+        return err
+    }
+    if opts.ASCII {
+        style = asciiStyle(style)
+    }
+    if opts.TableHeader && len(rows) > 0 && !opts.NoColor {
+        rows = append([][]string{headerCells(rows[0], opts)}, rows[1:]...)
+    }
+    widths := columnWidths(rows)
+    for i := range widths {
+        limit := opts.ColumnMaxWidth
+        if w, ok := opts.ColumnMaxWidths[i+1]; ok {
+            limit = w
+        }
+        if limit > 0 {
+            widths[i] = min(widths[i], limit)
+        }
+    }
+    if opts.CellWrap && opts.Width > 0 {
+        // The cells and their separators share the interior, less the
+        // padding on either side.
+        sepWidth := visualLength(style.Vertical) + 2
+        avail := opts.Width - 2*visualLength(style.Vertical) - 2 - sepWidth*max(len(widths)-1, 0)
+        widths = fitColumns(widths, avail)
+    }
+    lines, heights, err := tableLines(rows, widths, style.Vertical, opts.ColumnAlign, opts.WrapMode)
+    if err != nil {
+        return err
+    }
+    if !opts.TableHeader {
+        return Render(w, lines, opts)
+    }
+    opts.TitleSep, opts.HeaderLines = true, 1
+    if len(heights) > 0 {
+        opts.HeaderLines = heights[0]
+    }
+    l, err := prepare(lines, opts)
+    if err != nil {
+        return err
+    }
+    if visualLength(style.Vertical) == 1 && len(l.lines) > 0 {
+        l.crosses = columnLines(widths)
+    }
+    return l.draw(w)
+}
+
+// headerCells returns the cells of the header row in bold or in the header
+// color of opts.
+func headerCells(cells []string, opts Options) []string {
+    out := make([]string, len(cells))
+    for i, cell := range cells {
+        if cell != "" {
+            cell = Colorize(cell, parseColor(opts.HeaderColor))
+            if opts.HeaderBold {
+                cell = sgrBold + cell + sgrNoBold
+            }
+        }
+        out[i] = cell
+    }
+    return out
+}
+
+// columnWidths returns the width of the widest cell of each column of rows.
+func columnWidths(rows [][]string) []int {
+    var widths []int
+    for _, row := range rows {
+        for i, cell := range row {
+            if i == len(widths) {
+                widths = append(widths, 0)
+            }
+            widths[i] = max(widths[i], visualLength(cell))
+        }
+    }
+    return widths
+}
+
+// ParseColumnMaxWidth parses a comma separated list of column widths: a
+// number alone is the width of all columns, COLUMN=WIDTH that of the column
+// numbered from 1, as in "20,3=40".
+func ParseColumnMaxWidth(spec string) (all int, columns map[int]int, err error) {
+    for _, item := range strings.Split(spec, ",") {
+        item = strings.TrimSpace(item)
+        col, width, found := strings.Cut(item, "=")
+        if !found {
+            if all, err = strconv.Atoi(item); err != nil || all < 1 {
+                return 0, nil, fmt.Errorf("invalid column width %q, use WIDTH or COLUMN=WIDTH", item)
+            }
+            continue
+        }
+        c, err1 := strconv.Atoi(strings.TrimSpace(col))
+        w, err2 := strconv.Atoi(strings.TrimSpace(width))
+        if err1 != nil || err2 != nil || c < 1 || w < 1 {
+            return 0, nil, fmt.Errorf("invalid column width %q, use WIDTH or COLUMN=WIDTH", item)
+        }
+        if columns == nil {
+            columns = make(map[int]int)
+        }
+        columns[c] = w
+    }
+    return all, columns, nil
+}
+
+// fitColumns narrows the widest of widths, one column at a time, until they
+// add up to no more than avail. No column gets narrower than one column.
+func fitColumns(widths []int, avail int) []int {
+    widths = append([]int(nil), widths...)
+    total := 0
+    for _, w := range widths {
+        total += w
+    }
+    for total > avail {
+        widest := 0
+        for i, w := range widths {
+            if w > widths[widest] {
+                widest = i
+            }
+        }
+        if widths[widest] <= 1 {
+            break
+        }
+        widths[widest]--
+        total--
+    }
+    return widths
+}
+
+// columnLines returns the columns of the lines of tableLines taken by the
+// one column wide separators between columns of widths.
+func columnLines(widths []int) []int {
+    var cols []int
+    at := -2
+    for _, w := range widths[:max(len(widths)-1, 0)] {
+        // A cell is followed by a space, the separator and a space.
+        at += w + 3
+        cols = append(cols, at)
+    }
+    return cols
+}
+
+// tableLines lays out rows as columns of widths, aligned by aligns, and
+// separated by sep. Cells wider than their column are wrapped in mode onto
+// further lines, on which the other columns are left blank. The heights are
+// the number of lines of each row.
+func tableLines(rows [][]string, widths []int, sep string, aligns []Alignment, mode WrapMode) ([]string, []int, error) {
+    if len(aligns) > len(widths) {
+        return nil, nil, fmt.Errorf("%d column alignments for a table of %d columns", len(aligns), len(widths))
+    }
+
+    lines := make([]string, 0, len(rows))
+    heights := make([]int, len(rows))
+    for r, row := range rows {
+        wrapped := make([][]string, len(widths))
+        for i := range widths {
+            cell := ""
+            if i < len(row) {
+                cell = row[i]
+            }
+            wrapped[i] = []string{cell}
+            if visualLength(cell) > widths[i] {
+                wrapped[i] = Wrap(cell, widths[i], mode)
+            }
+            heights[r] = max(heights[r], len(wrapped[i]))
+        }
+        for k := range heights[r] {
+            cells := make([]string, len(widths))
+            for i := range widths {
+                cell := ""
+                if k < len(wrapped[i]) {
+                    cell = wrapped[i][k]
+                }
+                a := Left
+                if i < len(aligns) {
+                    a = aligns[i]
+                }
+                cells[i] = VisualPad(cell, widths[i], a)
+            }
+            lines = append(lines, strings.Join(cells, " "+sep+" "))
+        }
+    }
+    return lines, heights, nil
+}
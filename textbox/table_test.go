@@ -0,0 +1,176 @@
+package textbox
+
+import (
+    "bytes"
+    "reflect"
+    "testing"
+)
+
+func TestRenderTableColumnAlign(t *testing.T) {
+    rows := [][]string{{"name", "qty", "price"}, {"東京", "3", "1.50"}, {"apple", "12", "10.00"}}
+    opts := DefaultOptions()
+    opts.ColumnAlign = []Alignment{Center, Right}
+    var buf bytes.Buffer
+    if err := RenderTable(&buf, rows, opts); err != nil {
+        t.Fatal(err)
+    }
+    // The price column without an alignment is aligned left; the header
+    // is aligned like the other rows.
+    want := `┌─────────────────────┐
+│ name  │ qty │ price │
+│ 東京  │   3 │ 1.50  │
+│ apple │  12 │ 10.00 │
+└─────────────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("table:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    // A single alignment applies to the first column only.
+    opts.ColumnAlign = []Alignment{Right}
+    buf.Reset()
+    if err := RenderTable(&buf, rows, opts); err != nil {
+        t.Fatal(err)
+    }
+    want = `┌─────────────────────┐
+│  name │ qty │ price │
+│  東京 │ 3   │ 1.50  │
+│ apple │ 12  │ 10.00 │
+└─────────────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("table with one alignment:\n%s\nwant:\n%s", buf.String(), want)
+    }
+
+    opts.ColumnAlign = []Alignment{Left, Left, Left, Left}
+    if err := RenderTable(&buf, rows, opts); err == nil {
+        t.Error("four column alignments for three columns succeeded, want an error")
+    }
+}
+
+func TestParseColumnAlign(t *testing.T) {
+    got, err := ParseColumnAlign("l, R,center")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := []Alignment{Left, Right, Center}; !reflect.DeepEqual(got, want) {
+        t.Errorf("ParseColumnAlign = %v, want %v", got, want)
+    }
+    for _, spec := range []string{"x", "l,,r", "justify"} {
+        if _, err := ParseColumnAlign(spec); err == nil {
+            t.Errorf("ParseColumnAlign(%q) succeeded, want an error", spec)
+        }
+    }
+}
+
+func TestRenderTableHeader(t *testing.T) {
+    rows := [][]string{{"name", "qty", "note"}, {"apple", "3"}, {"kiwi", "12", "ripe"}}
+    tests := []struct {
+        name string
+        opts func(*Options)
+        want string
+    }{
+        {"plain", func(o *Options) {}, `┌────────────────────┐
+│ name  │ qty │ note │
+├───────┼─────┼──────┤
+│ apple │ 3   │      │
+│ kiwi  │ 12  │ ripe │
+└────────────────────┘
+`},
+        {"double", func(o *Options) { o.Style = "3" }, `╔════════════════════╗
+║ name  ║ qty ║ note ║
+╠═══════╬═════╬══════╣
+║ apple ║ 3   ║      ║
+║ kiwi  ║ 12  ║ ripe ║
+╚════════════════════╝
+`},
+        {"ascii", func(o *Options) { o.ASCII = true }, `+--------------------+
+| name  | qty | note |
++-------+-----+------+
+| apple | 3   |      |
+| kiwi  | 12  | ripe |
++--------------------+
+`},
+        {"bold", func(o *Options) { o.NoColor, o.HeaderBold = false, true }, "┌────────────────────┐\n" +
+            "│ \x1b[1mname\x1b[22m  │ \x1b[1mqty\x1b[22m │ \x1b[1mnote\x1b[22m │\n" +
+            `├───────┼─────┼──────┤
+│ apple │ 3   │      │
+│ kiwi  │ 12  │ ripe │
+└────────────────────┘
+`},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.TableHeader, opts.NoColor = true, true
+            tt.opts(&opts)
+            var buf bytes.Buffer
+            if err := RenderTable(&buf, rows, opts); err != nil {
+                t.Fatal(err)
+            }
+            if buf.String() != tt.want {
+                t.Errorf("table:\n%s\nwant:\n%s", buf.String(), tt.want)
+            }
+        })
+    }
+}
+
+func TestRenderTableCellWrap(t *testing.T) {
+    rows := [][]string{{"id", "description"}, {"1", "a rather long description"}, {"2", "short"}}
+    opts := DefaultOptions()
+    opts.TableHeader, opts.NoColor, opts.CellWrap, opts.Width = true, true, true, 18
+    var buf bytes.Buffer
+    if err := RenderTable(&buf, rows, opts); err != nil {
+        t.Fatal(err)
+    }
+    // The description column is narrowed to fit; a word too long for it
+    // is broken and the divider goes below the whole header row.
+    want := `┌────────────────┐
+│ id │ descripti │
+│    │ on        │
+├────┼───────────┤
+│ 1  │ a rather  │
+│    │ long      │
+│    │ descripti │
+│    │ on        │
+│ 2  │ short     │
+└────────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("table:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
+
+func TestRenderTableColumnMaxWidth(t *testing.T) {
+    rows := [][]string{{"1", "one two three", "four five"}, {"2", "six", "seven"}}
+    opts := DefaultOptions()
+    opts.ColumnMaxWidth, opts.ColumnMaxWidths = 5, map[int]int{2: 7}
+    var buf bytes.Buffer
+    if err := RenderTable(&buf, rows, opts); err != nil {
+        t.Fatal(err)
+    }
+    want := `┌─────────────────────┐
+│ 1 │ one two │ four  │
+│   │ three   │ five  │
+│ 2 │ six     │ seven │
+└─────────────────────┘
+`
+    if buf.String() != want {
+        t.Errorf("table:\n%s\nwant:\n%s", buf.String(), want)
+    }
+}
+
+func TestParseColumnMaxWidth(t *testing.T) {
+    all, columns, err := ParseColumnMaxWidth("20, 3=40,1=5")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := map[int]int{3: 40, 1: 5}; all != 20 || !reflect.DeepEqual(columns, want) {
+        t.Errorf("ParseColumnMaxWidth = %d, %v, want 20, %v", all, columns, want)
+    }
+    for _, spec := range []string{"", "x", "0", "3=", "0=10", "2=-1"} {
+        if _, _, err := ParseColumnMaxWidth(spec); err == nil {
+            t.Errorf("ParseColumnMaxWidth(%q) succeeded, want an error", spec)
+        }
+    }
+}
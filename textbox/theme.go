@@ -0,0 +1,166 @@
+package textbox
+
+import (
+    "encoding/json"
+    "fmt"
+    "regexp"
+    "sort"
+    "sync"
+)
+
+// HighlightRule colors the content lines matching Pattern in Color.
+type HighlightRule struct {
+    Pattern *regexp.Regexp
+    Color   ANSIColor
+}
+
+// Theme combines the border glyphs of a style with the colors of the box. A
+// nil color leaves that part uncolored.
+type Theme struct {
+    BoxStyle
+    BorderColor  ANSIColor
+    TitleColor   ANSIColor
+    ContentColor ANSIColor
+    // HighlightRules color the matching content lines instead of
+    // ContentColor. The first matching rule wins.
+    HighlightRules []HighlightRule
+    // Shadow draws a shadow below and to the right of the box.
+    Shadow bool
+}
+
+// themeJSON is the JSON form of a Theme. Colors are written in a form
+// ParseANSIColor reads back.
+type themeJSON struct {
+    Style          []string        `json:"style"`
+    BorderColor    string          `json:"border_color,omitempty"`
+    TitleColor     string          `json:"title_color,omitempty"`
+    ContentColor   string          `json:"content_color,omitempty"`
+    HighlightRules []highlightJSON `json:"highlight_rules,omitempty"`
+    Shadow         bool            `json:"shadow,omitempty"`
+}
+
+// highlightJSON is the JSON form of a HighlightRule.
+type highlightJSON struct {
+    Pattern string `json:"pattern"`
+    Color   string `json:"color"`
+}
+
+// MarshalJSON encodes the theme with the style as its eight glyphs.
+func (t Theme) MarshalJSON() ([]byte, error) {
+    j := themeJSON{
+        Style:        t.Glyphs(),
+        BorderColor:  colorString(t.BorderColor),
+        TitleColor:   colorString(t.TitleColor),
+        ContentColor: colorString(t.ContentColor),
+        Shadow:       t.Shadow,
+    }
+    for _, r := range t.HighlightRules {
+        j.HighlightRules = append(j.HighlightRules, highlightJSON{r.Pattern.String(), colorString(r.Color)})
+    }
+    return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a theme written by MarshalJSON.
+func (t *Theme) UnmarshalJSON(data []byte) error {
+    var j themeJSON
+    if err := json.Unmarshal(data, &j); err != nil {
+        return err
+    }
+    style, err := NewBoxStyle(j.Style...)
+    if err != nil {
+        return fmt.Errorf("theme style: %v", err)
+    }
+    theme := Theme{BoxStyle: style, Shadow: j.Shadow}
+    for _, c := range []struct {
+        s string
+        p *ANSIColor
+    }{
+        {j.BorderColor, &theme.BorderColor},
+        {j.TitleColor, &theme.TitleColor},
+        {j.ContentColor, &theme.ContentColor},
+    } {
+        if c.s == "" {
+            continue
+        }
+        if *c.p, err = ParseANSIColor(c.s); err != nil {
+            return err
+        }
+    }
+    for _, h := range j.HighlightRules {
+        pattern, err := regexp.Compile(h.Pattern)
+        if err != nil {
+            return err
+        }
+        color, err := ParseANSIColor(h.Color)
+        if err != nil {
+            return err
+        }
+        theme.HighlightRules = append(theme.HighlightRules, HighlightRule{pattern, color})
+    }
+    *t = theme
+    return nil
+}
+
+// themes holds the themes that can be selected by name.
+var themes = struct {
+    sync.RWMutex
+    m map[string]Theme
+}{m: map[string]Theme{
+    "ocean": {
+        BoxStyle:    builtinStyles[1].style,
+        BorderColor: Cyan, TitleColor: BrightWhite,
+    },
+    "alert": {
+        BoxStyle:    builtinStyles[2].style,
+        BorderColor: Red, TitleColor: BrightYellow,
+        Shadow: true,
+    },
+}}
+
+// RegisterTheme makes t selectable as Options.Theme under name. It fails if
+// the name is already taken. It is safe for concurrent use.
+func RegisterTheme(name string, t Theme) error {
+    if name == "" {
+        return fmt.Errorf("invalid theme name %q", name)
+    }
+    if err := checkGlyphs(t.Glyphs()); err != nil {
+        return fmt.Errorf("theme %q: %v", name, err)
+    }
+    themes.Lock()
+    defer themes.Unlock()
+    if _, ok := themes.m[name]; ok {
+        return fmt.Errorf("theme %q already exists", name)
+    }
+    themes.m[name] = t
+    return nil
+}
+
+// Themes returns the sorted names of all registered themes.
+func Themes() []string {
+    themes.RLock()
+    defer themes.RUnlock()
+    var names []string
+    for name := range themes.m {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// LookupTheme returns the theme registered as name.
+func LookupTheme(name string) (Theme, bool) {
+    themes.RLock()
+    defer themes.RUnlock()
+    t, ok := themes.m[name]
+    return t, ok
+}
+
+// highlight returns the color of the first rule matching line, or fallback.
+func highlight(rules []HighlightRule, line string, fallback ANSIColor) ANSIColor {
+    for _, r := range rules {
+        if r.Pattern.MatchString(stripANSI(line)) {
+            return r.Color
+        }
+    }
+    return fallback
+}
@@ -0,0 +1,75 @@
+package textbox
+
+import (
+    "fmt"
+    "strings"
+)
+
+// TitleFit selects how a title wider than the content is fitted into the
+// top border.
+type TitleFit int
+
+// Title fits.
+const (
+    // TitleGrow widens the box for the title.
+    TitleGrow TitleFit = iota
+    // TitleClip cuts the title to the width of the box with an ellipsis.
+    TitleClip
+)
+
+// titleFitNames are the names of the title fits, indexed by fit.
+var titleFitNames = []string{"grow", "clip"}
+
+func (f TitleFit) String() string {
+    if f >= 0 && int(f) < len(titleFitNames) {
+        return titleFitNames[f]
+    }
+    return fmt.Sprintf("TitleFit(%d)", int(f))
+}
+
+// ParseTitleFit returns the title fit named grow or clip.
+func ParseTitleFit(name string) (TitleFit, error) {
+    for i, n := range titleFitNames {
+        if strings.EqualFold(name, n) {
+            return TitleFit(i), nil
+        }
+    }
+    return TitleGrow, fmt.Errorf("invalid title fit %q, use grow or clip", name)
+}
+
+// MarshalText encodes the fit as its name.
+func (f TitleFit) MarshalText() ([]byte, error) {
+    if f < 0 || int(f) >= len(titleFitNames) {
+        return nil, fmt.Errorf("invalid title fit %d", int(f))
+    }
+    return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a title fit name.
+func (f *TitleFit) UnmarshalText(text []byte) error {
+    parsed, err := ParseTitleFit(string(text))
+    if err == nil {
+        *f = parsed
+    }
+    return err
+}
+
+// Set implements flag.Value.
+func (f *TitleFit) Set(name string) error {
+    return f.UnmarshalText([]byte(name))
+}
+
+// ellipsis ends titles cut by TitleClip.
+const ellipsis = "…"
+
+// clipTitle cuts title to width columns, ending it with an ellipsis if it
+// had to be cut. Without room for the ellipsis the title is dropped.
+func clipTitle(title string, width int) string {
+    if visualLength(title) <= width {
+        return title
+    }
+    if width < visualLength(ellipsis) {
+        return ""
+    }
+    return strings.TrimRight(truncate(title, width-visualLength(ellipsis)), " ") + ellipsis
+}
@@ -0,0 +1,140 @@
+package textbox
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// parseTOML decodes the subset of TOML used by configuration files: one
+// "key = value" pair per line with string, integer or boolean values, or
+// arrays and inline tables of them, and # comments. Tables are not
+// supported.
+func parseTOML(data string) (map[string]any, error) {
+    values := make(map[string]any)
+    for n, line := range strings.Split(data, "\n") {
+        line = strings.TrimSpace(stripComment(line))
+        if line == "" {
+            continue
+        }
+        key, raw, ok := strings.Cut(line, "=")
+        if !ok {
+            return nil, fmt.Errorf("line %d: expected key = value", n+1)
+        }
+        key, raw = strings.TrimSpace(key), strings.TrimSpace(raw)
+        value, err := parseTOMLValue(raw)
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", n+1, err)
+        }
+        values[key] = value
+    }
+    return values, nil
+}
+
+// stripComment removes a trailing # comment that is not inside a string.
+func stripComment(line string) string {
+    var quote byte
+    for i := 0; i < len(line); i++ {
+        c := line[i]
+        switch {
+        case quote == '"' && c == '\\':
+            i++ // skip the escaped byte
+        case quote != 0 && c == quote:
+            quote = 0
+        case quote == 0 && (c == '"' || c == '\''):
+            quote = c
+        case quote == 0 && c == '#':
+            return line[:i]
+        }
+    }
+    return line
+}
+
+// parseTOMLValue decodes a string, integer, float or boolean, or an array or
+// inline table of them.
+func parseTOMLValue(raw string) (any, error) {
+    switch {
+    case strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]"):
+        values := []any{}
+        for _, item := range splitTOMLList(raw[1 : len(raw)-1]) {
+            value, err := parseTOMLScalar(item)
+            if err != nil {
+                return nil, err
+            }
+            values = append(values, value)
+        }
+        return values, nil
+    case strings.HasPrefix(raw, "{") && strings.HasSuffix(raw, "}"):
+        values := make(map[string]any)
+        for _, item := range splitTOMLList(raw[1 : len(raw)-1]) {
+            key, rawValue, ok := strings.Cut(item, "=")
+            if !ok {
+                return nil, fmt.Errorf("expected key = value in %s", raw)
+            }
+            key = strings.TrimSpace(key)
+            if strings.HasPrefix(key, `"`) {
+                var err error
+                if key, err = strconv.Unquote(key); err != nil {
+                    return nil, fmt.Errorf("invalid key in %s", raw)
+                }
+            }
+            value, err := parseTOMLScalar(strings.TrimSpace(rawValue))
+            if err != nil {
+                return nil, err
+            }
+            values[key] = value
+        }
+        return values, nil
+    }
+    return parseTOMLScalar(raw)
+}
+
+// splitTOMLList splits the items of an array or inline table at the commas
+// outside strings. A trailing comma is allowed.
+func splitTOMLList(s string) []string {
+    var items []string
+    var quote byte
+    start := 0
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        switch {
+        case quote == '"' && c == '\\':
+            i++ // skip the escaped byte
+        case quote != 0 && c == quote:
+            quote = 0
+        case quote == 0 && (c == '"' || c == '\''):
+            quote = c
+        case quote == 0 && c == ',':
+            items = append(items, strings.TrimSpace(s[start:i]))
+            start = i + 1
+        }
+    }
+    if last := strings.TrimSpace(s[start:]); last != "" {
+        items = append(items, last)
+    }
+    return items
+}
+
+// parseTOMLScalar decodes a string, integer, float or boolean.
+func parseTOMLScalar(raw string) (any, error) {
+    switch {
+    case raw == "true":
+        return true, nil
+    case raw == "false":
+        return false, nil
+    case strings.HasPrefix(raw, `"`):
+        return strconv.Unquote(raw)
+    case strings.HasPrefix(raw, "'") && strings.HasSuffix(raw, "'") && len(raw) >= 2:
+        return raw[1 : len(raw)-1], nil
+    case strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, "{"):
+        return nil, fmt.Errorf("nested arrays and tables are not supported")
+    }
+    if n, err := strconv.Atoi(strings.ReplaceAll(raw, "_", "")); err == nil {
+        return n, nil
+    }
+    f, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64)
+    if err != nil {
+        return nil, fmt.Errorf("invalid value %s", raw)
+    }
+    return f, nil
+}
@@ -0,0 +1,72 @@
+package textbox
+
+import "strings"
+
+// treeLines replaces every run of lines indented by at least
+// opts.TreeIndent columns with a box of those lines, one indentation level
+// removed. The nested lines are laid out the same way, so that every level
+// is inset in its parent. The lines at the top level are wrapped.
+func treeLines(lines []string, style BoxStyle, opts Options) []string {
+    unit := max(opts.TreeIndent, 1)
+    var out, top []string
+    for i := 0; i < len(lines); {
+        if indentWidth(lines[i], unit) < unit {
+            top = append(top, lines[i])
+            i++
+            continue
+        }
+        out = append(out, wrapLines(top, opts)...)
+        top = nil
+
+        var children []string
+        for ; i < len(lines) && indentWidth(lines[i], unit) >= unit; i++ {
+            children = append(children, dedent(lines[i], unit))
+        }
+        out = append(out, treeBox(children, style, opts)...)
+    }
+    return append(out, wrapLines(top, opts)...)
+}
+
+// treeBox frames the lines of one nesting level.
+func treeBox(lines []string, style BoxStyle, opts Options) []string {
+    inner := DefaultOptions()
+    inner.NoColor, inner.ASCII, inner.FillBlock = opts.NoColor, opts.ASCII, opts.FillBlock
+    inner.Theme, inner.BorderColor = opts.Theme, opts.BorderColor
+    inner.Wrap, inner.WrapMode, inner.WrapIndent = opts.Wrap, opts.WrapMode, opts.WrapIndent
+    inner.ContinueChar, inner.Hyphenate = opts.ContinueChar, opts.Hyphenate
+    inner.Tree, inner.TreeIndent = true, opts.TreeIndent
+
+    var buf strings.Builder
+    drawBox(&buf, lines, style, inner)
+    return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// indentWidth returns the columns of leading whitespace of line, counting a
+// tab as unit columns.
+func indentWidth(line string, unit int) int {
+    w := 0
+    for _, r := range line {
+        switch r {
+        case ' ':
+            w++
+        case '\t':
+            w += unit
+        default:
+            return w
+        }
+    }
+    // Blank lines belong to no level.
+    return 0
+}
+
+// dedent removes unit columns of leading whitespace from line.
+func dedent(line string, unit int) string {
+    for w := 0; w < unit && line != ""; line = line[1:] {
+        if line[0] == '\t' {
+            w += unit
+        } else {
+            w++
+        }
+    }
+    return line
+}
@@ -0,0 +1,330 @@
+package textbox
+
+import (
+    "fmt"
+    "math"
+    "strings"
+)
+
+// WrapMode selects where Wrap breaks lines.
+type WrapMode string
+
+// Wrap modes.
+const (
+    // Soft breaks at spaces and after hyphens and zero width spaces
+    // (U+200B), but never next to a word joiner (U+2060). Words longer than
+    // the width are broken where they overflow. Wrap never breaks at soft
+    // hyphens (U+00AD); boxes do with Options.Hyphenate.
+    Soft WrapMode = "soft"
+    // Hard breaks at exactly the width regardless of words.
+    Hard WrapMode = "hard"
+)
+
+// Wrap breaks s into lines of at most width columns. Escape sequences take
+// no room and are kept; colors and attributes active at a break are reset at
+// the end of the line and set again on the next one. A width below one
+// returns s unchanged.
+func Wrap(s string, width int, mode WrapMode) []string {
+    if width <= 0 {
+        return []string{s}
+    }
+    if mode == Hard {
+        return hardWrap(s, width, "", "")
+    }
+    return softWrap(s, width, "", false)
+}
+
+// wrapLines breaks every line wider than opts.Wrap columns. With
+// opts.PreserveIndent continuation lines repeat the indentation of their line.
+func wrapLines(lines []string, opts Options) []string {
+    if opts.Wrap <= 0 {
+        return lines
+    }
+    var wrapped []string
+    for _, line := range lines {
+        indent := strings.Repeat(" ", max(opts.WrapIndent, 0))
+        if opts.PreserveIndent {
+            indent = preservedIndent(line, indent, opts.Wrap)
+        }
+        if opts.WrapMode == Hard {
+            wrapped = append(wrapped, hardWrap(line, opts.Wrap, indent, opts.ContinueChar)...)
+        } else {
+            wrapped = append(wrapped, softWrap(line, opts.Wrap, indent, opts.Hyphenate)...)
+        }
+    }
+    return wrapped
+}
+
+// preservedIndent returns the leading whitespace of line followed by
+// indent. An indentation leaving less than one column of width, counting
+// tabs as one column, is dropped in favor of indent alone.
+func preservedIndent(line, indent string, width int) string {
+    lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+    if visualLength(lead)+strings.Count(lead, "\t")+visualLength(indent) >= width {
+        return indent
+    }
+    return lead + indent
+}
+
+// validate checks the option values that are restricted to a set of names.
+func (o Options) validate() error {
+    if o.WrapMode != Soft && o.WrapMode != Hard {
+        return fmt.Errorf("invalid wrap mode %q, use soft or hard", o.WrapMode)
+    }
+    if o.Wrap > 0 && o.WrapIndent >= o.Wrap {
+        return fmt.Errorf("wrap indent %d leaves no room in lines of %d columns", o.WrapIndent, o.Wrap)
+    }
+    for _, t := range []struct{ name, value string }{
+        {"title", o.Title},
+        {"footer", o.Footer},
+    } {
+        if strings.ContainsAny(t.value, "\r\n") {
+            return fmt.Errorf("%s %q contains a line break, which cannot be drawn in the border", t.name, t.value)
+        }
+    }
+    switch o.ControlChars {
+    case "", controlsKeep, controlsStrip, controlsCaret, controlsPictures:
+    default:
+        return fmt.Errorf("invalid control character mode %q, use strip, caret, pictures or keep", o.ControlChars)
+    }
+    if o.Progress > 1 || math.IsNaN(o.Progress) {
+        return fmt.Errorf("progress %v is not between 0 and 1", o.Progress)
+    }
+    if o.Progress >= 0 {
+        if err := checkGlyphs([]string{o.ProgressFill, o.ProgressEmpty}); err != nil {
+            return fmt.Errorf("progress bar: %v", err)
+        }
+    }
+    switch o.Normalize {
+    case "", normalizeNone, normalizeNFC, normalizeNFKC:
+    default:
+        return fmt.Errorf("invalid normalization %q, use nfc, nfkc or none", o.Normalize)
+    }
+    if o.Sanitize != "" && o.Sanitize != "off" {
+        if _, err := ParsePolicy(o.Sanitize); err != nil {
+            return err
+        }
+    }
+    for _, a := range []struct {
+        name  string
+        value Alignment
+    }{
+        {"title", o.TitleAlign},
+        {"title-only box", o.TitleOnlyAlign},
+        {"footer", o.FooterAlign},
+    } {
+        if a.value != Left && a.value != Center && a.value != Right {
+            return fmt.Errorf("invalid %s alignment %v, use left, center or right", a.name, a.value)
+        }
+    }
+    if o.ColumnMaxWidth < 0 {
+        return fmt.Errorf("invalid column width %d", o.ColumnMaxWidth)
+    }
+    for col, width := range o.ColumnMaxWidths {
+        if col < 1 || width < 0 {
+            return fmt.Errorf("invalid width %d of column %d", width, col)
+        }
+    }
+    for _, a := range o.ColumnAlign {
+        if a != Left && a != Center && a != Right {
+            return fmt.Errorf("invalid column alignment %v, use left, center or right", a)
+        }
+    }
+    if _, err := o.TextAlign.MarshalText(); err != nil {
+        return err
+    }
+    if _, err := o.Overflow.MarshalText(); err != nil {
+        return err
+    }
+    if _, err := o.Shadow.MarshalText(); err != nil {
+        return err
+    }
+    if _, err := o.TitleFit.MarshalText(); err != nil {
+        return err
+    }
+    for _, c := range []struct{ name, value string }{
+        {"border color", o.BorderColor},
+        {"title color", o.TitleColor},
+        {"header color", o.HeaderColor},
+    } {
+        if c.value == "" {
+            continue
+        }
+        if _, err := ParseANSIColor(c.value); err != nil {
+            return fmt.Errorf("%s: %v", c.name, err)
+        }
+    }
+    return nil
+}
+
+// cellsWidth returns the width of cs in columns.
+func cellsWidth(cs []cell) int {
+    w := 0
+    for _, c := range cs {
+        w += c.width
+    }
+    return w
+}
+
+// fitCells returns how many leading cells fit into width columns, but at
+// least one cell with a width.
+func fitCells(cs []cell, width int) int {
+    n, w := 0, 0
+    for n < len(cs) && w+cs[n].width <= width {
+        w += cs[n].width
+        n++
+    }
+    for n < len(cs) && w == 0 {
+        w += cs[n].width
+        n++
+    }
+    return n
+}
+
+// trimSpaceCells removes the spaces at the start (left) or end of cs.
+func trimSpaceCells(cs []cell, left bool) []cell {
+    for len(cs) > 0 {
+        i := len(cs) - 1
+        if left {
+            i = 0
+        }
+        if cs[i].s != " " {
+            break
+        }
+        if left {
+            cs = cs[1:]
+        } else {
+            cs = cs[:i]
+        }
+    }
+    return cs
+}
+
+// wrapper joins broken pieces and carries the colors and attributes active
+// at a break over to the next piece.
+type wrapper struct {
+    pieces []string
+    active []string
+}
+
+// add appends the piece made of prefix, cs and suffix.
+func (wr *wrapper) add(prefix string, cs []cell, suffix string) {
+    var b strings.Builder
+    b.WriteString(prefix)
+    b.WriteString(strings.Join(wr.active, ""))
+    for _, c := range cs {
+        b.WriteString(c.s)
+        switch {
+        case c.isReset():
+            wr.active = nil
+        case c.isSGR():
+            wr.active = append(wr.active, c.s)
+        }
+    }
+    if len(wr.active) > 0 {
+        b.WriteString("\x1b[0m")
+    }
+    b.WriteString(suffix)
+    wr.pieces = append(wr.pieces, b.String())
+}
+
+// last appends the final piece, which keeps its own resets.
+func (wr *wrapper) last(prefix string, cs []cell) []string {
+    var b strings.Builder
+    b.WriteString(prefix)
+    b.WriteString(strings.Join(wr.active, ""))
+    for _, c := range cs {
+        b.WriteString(c.s)
+    }
+    return append(wr.pieces, b.String())
+}
+
+// softWrap breaks line at spaces and after hyphens and zero width spaces so
+// that no piece is wider than width, but not next to a word joiner. With
+// hyphenate it also breaks at soft hyphens, writing a hyphen in their
+// place. Words longer than width are broken where they overflow, with
+// hyphenate ending the piece with a hyphen. Continuation pieces start with
+// indent, which counts towards their width.
+func softWrap(line string, width int, indent string, hyphenate bool) []string {
+    var wr wrapper
+    cs := cells(line)
+    prefix := ""
+    // Every piece takes at least one cell, so the loop ends even if the
+    // prefix leaves no room.
+    for len(cs) > 0 && visualLength(prefix)+cellsWidth(cs) > width {
+        avail := width - visualLength(prefix)
+        n := fitCells(cs, avail)
+        // The leading indentation of the line is no break opportunity.
+        lead := 0
+        for lead < len(cs) && cs[lead].s == " " {
+            lead++
+        }
+        if lead >= n && lead < len(cs) {
+            // Indentation filling the line is dropped.
+            cs = cs[lead:]
+            continue
+        }
+        // The piece ends before cut and the rest starts after skip cells.
+        cut, skip, hyphen := -1, 0, ""
+        for i := lead; i < n; i++ {
+            switch cs[i].s {
+            case " ":
+                if !joined(cs, i-1) && !joined(cs, i+1) {
+                    cut, skip, hyphen = i, 0, ""
+                }
+            case "-", zeroWidthSpace:
+                if !joined(cs, i+1) {
+                    cut, skip, hyphen = i+1, 0, ""
+                }
+            case softHyphen:
+                if hyphenate && cellsWidth(cs[:i]) < avail {
+                    cut, skip, hyphen = i, 1, "-"
+                }
+            }
+        }
+        if n >= lead && n < len(cs) && cs[n].s == " " && !joined(cs, n-1) && !joined(cs, n+1) {
+            cut, skip, hyphen = n, 0, ""
+        }
+        if cut <= lead {
+            cut, skip, hyphen = n, 0, ""
+            if hyphenate && avail > 1 {
+                cut, hyphen = fitCells(cs, avail-1), "-"
+            }
+        }
+        wr.add(prefix, trimSpaceCells(cs[:cut], false), hyphen)
+        cs = trimSpaceCells(cs[cut+skip:], true)
+        prefix = indent
+    }
+    return wr.last(prefix, cs)
+}
+
+// Break hints for soft wrapping. All are zero columns wide.
+const (
+    // softHyphen allows a break, shown as a hyphen, with hyphenation.
+    softHyphen = "\u00ad"
+    // zeroWidthSpace allows a break after it.
+    zeroWidthSpace = "\u200b"
+    // wordJoiner forbids a break between its neighbors.
+    wordJoiner = "\u2060"
+)
+
+// joined reports whether cs[i] is a word joiner.
+func joined(cs []cell, i int) bool {
+    return i >= 0 && i < len(cs) && cs[i].s == wordJoiner
+}
+
+// hardWrap breaks line every width columns regardless of words and ends
+// every broken piece with cont. Continuation pieces start with indent, which
+// counts towards their width.
+func hardWrap(line string, width int, indent, cont string) []string {
+    var wr wrapper
+    cs := cells(line)
+    prefix := ""
+    for len(cs) > 0 && visualLength(prefix)+cellsWidth(cs) > width {
+        n := fitCells(cs, width-visualLength(prefix+cont))
+        wr.add(prefix, cs[:n], cont)
+        cs = cs[n:]
+        prefix = indent
+    }
+    return wr.last(prefix, cs)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+
+    "box/textbox"
+)
+
+// loadTheme registers the theme file named by theme under its path, unless
+// theme names a registered theme.
+func loadTheme(theme string) error {
+    if _, ok := textbox.LookupTheme(theme); ok || theme == "" {
+        return nil
+    }
+    data, err := os.ReadFile(theme)
+    if errors.Is(err, os.ErrNotExist) {
+        return fmt.Errorf("unknown theme %q", theme)
+    } else if err != nil {
+        return err
+    }
+    var t textbox.Theme
+    if err := json.Unmarshal(data, &t); err != nil {
+        return fmt.Errorf("%s: %v", theme, err)
+    }
+    return textbox.RegisterTheme(theme, t)
+}
+
+// themesCommand declares the options of the themes command.
+func themesCommand(o *optionSet) func(args []string) error {
+    o.synopsis = "list | show NAME"
+
+    return func(args []string) error {
+        if len(args) == 0 {
+            o.fs.Usage()
+            return errors.New("themes: missing action")
+        }
+        switch args[0] {
+        case "list":
+            for _, name := range textbox.Themes() {
+                if _, err := fmt.Fprintln(os.Stdout, name); err != nil {
+                    return err
+                }
+            }
+        case "show":
+            if len(args) != 2 {
+                return errors.New("themes show: expected a theme name")
+            }
+            if err := loadTheme(args[1]); err != nil {
+                return err
+            }
+            t, _ := textbox.LookupTheme(args[1])
+            data, err := json.MarshalIndent(t, "", "    ")
+            if err != nil {
+                return err
+            }
+            if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+                return err
+            }
+        default:
+            return fmt.Errorf("themes: unknown action %q", args[0])
+        }
+        return nil
+    }
+}
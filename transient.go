@@ -0,0 +1,53 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+
+    "box/textbox"
+)
+
+// eraseAfter waits for delay, or until box is interrupted, and then erases
+// the rows shown took on the terminal w, leaving the cursor where they
+// started. An interrupted wait still erases them but ends with an
+// *errInterrupted.
+func eraseAfter(w io.Writer, shown string, delay time.Duration) error {
+    ctx, stop := interruptContext()
+    defer stop()
+    timer := time.NewTimer(delay)
+    defer timer.Stop()
+    select {
+    case <-timer.C:
+    case <-ctx.Done():
+    }
+    width, _ := terminalWidth()
+    if _, err := io.WriteString(w, eraseSequence(shown, width)); err != nil {
+        return err
+    }
+    if ctx.Err() != nil {
+        return context.Cause(ctx)
+    }
+    return nil
+}
+
+// eraseSequence returns the escape sequences moving the cursor up to the
+// first of the rows shown took on a terminal width columns wide and
+// erasing them to the end of the screen. Lines wider than the terminal take
+// several rows; without a width every line counts as one.
+func eraseSequence(shown string, width int) string {
+    if shown == "" {
+        return ""
+    }
+    rows := 0
+    for _, line := range strings.Split(strings.TrimSuffix(shown, "\n"), "\n") {
+        n := 1
+        if w := textbox.VisualWidth(textbox.Sanitize(line, textbox.Strict)); width > 0 && w > width {
+            n = (w + width - 1) / width
+        }
+        rows += n
+    }
+    return fmt.Sprintf("\r\x1b[%dA\x1b[J", rows)
+}
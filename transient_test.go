@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestEraseSequence(t *testing.T) {
+    for _, tt := range []struct {
+        shown string
+        width int
+        want  string
+    }{
+        {"┌───┐\n│ x │\n└───┘\n", 80, "\r\x1b[3A\x1b[J"},
+        // A line wider than the terminal takes two rows.
+        {"┌───┐\n│ x │\n└───┘\n", 3, "\r\x1b[6A\x1b[J"},
+        {"\x1b[31m┌───┐\x1b[0m\n", 5, "\r\x1b[1A\x1b[J"},
+        {"a\nb\n", 0, "\r\x1b[2A\x1b[J"},
+        // Emoji sequences are measured as the box measures them.
+        {"│ \U0001F1E9\U0001F1EA\U0001F1E9\U0001F1EA │\n", 7, "\r\x1b[2A\x1b[J"},
+        {"", 80, ""},
+    } {
+        if got := eraseSequence(tt.shown, tt.width); got != tt.want {
+            t.Errorf("eraseSequence(%q, %d) = %q, want %q", tt.shown, tt.width, got, tt.want)
+        }
+    }
+}
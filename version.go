@@ -0,0 +1,65 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "runtime"
+    "runtime/debug"
+)
+
+// Build information, set with
+// -ldflags "-X main.version=1.2.3 -X main.commit=abc1234 -X main.date=2024-01-02".
+var (
+    version string
+    commit  string
+    date    string
+)
+
+// buildInfo returns the version, commit and build date. Values not set at
+// link time are taken from the module build information embedded by
+// go install and go build.
+func buildInfo() (v, c, d string) {
+    v, c, d = version, commit, date
+    if info, ok := debug.ReadBuildInfo(); ok {
+        if v == "" && info.Main.Version != "" {
+            v = info.Main.Version
+        }
+        for _, s := range info.Settings {
+            switch {
+            case s.Key == "vcs.revision" && c == "":
+                c = s.Value
+            case s.Key == "vcs.time" && d == "":
+                d = s.Value
+            }
+        }
+    }
+    if v == "" {
+        v = "(devel)"
+    }
+    if c == "" {
+        c = "unknown"
+    }
+    if d == "" {
+        d = "unknown"
+    }
+    return v, c, d
+}
+
+// printVersion writes the build information on one line, or one item per
+// line if verbose is set.
+func printVersion(w io.Writer, prog string, verbose bool) {
+    v, c, d := buildInfo()
+    if !verbose {
+        if len(c) > 12 {
+            c = c[:12]
+        }
+        fmt.Fprintf(w, "%s %s (commit %s, built %s)\n", prog, v, c, d)
+        return
+    }
+    fmt.Fprintf(w, "%s\n", prog)
+    fmt.Fprintf(w, "  version:  %s\n", v)
+    fmt.Fprintf(w, "  commit:   %s\n", c)
+    fmt.Fprintf(w, "  built:    %s\n", d)
+    fmt.Fprintf(w, "  go:       %s\n", runtime.Version())
+    fmt.Fprintf(w, "  platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+}